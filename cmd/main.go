@@ -4,15 +4,51 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/fatih/color"
 
 	serverHandler "github.com/Bearaujus/minecraft-server-api/internal/handler/server"
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
 	serverResource "github.com/Bearaujus/minecraft-server-api/internal/resource/server"
 )
 
 func main() {
-	var serverResource = serverResource.NewServerResource()
+	var orphanPolicy = model.OrphanPolicy(os.Getenv("MSA_ORPHAN_POLICY"))
+	if orphanPolicy == "" {
+		orphanPolicy = model.OrphanPolicyAdopt
+	}
+	if err := orphanPolicy.Validate(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var javaDirs []string
+	if sJavaDirs := os.Getenv("MSA_JAVA_DIRS"); sJavaDirs != "" {
+		javaDirs = filepath.SplitList(sJavaDirs)
+	}
+
+	var memoryReserveMB = model.DEFAULT_MEMORY_RESERVE_MB
+	if sMemoryReserveMB := os.Getenv("MSA_MEMORY_RESERVE_MB"); sMemoryReserveMB != "" {
+		v, err := strconv.Atoi(sMemoryReserveMB)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if v < 0 {
+			fmt.Println("MSA_MEMORY_RESERVE_MB cannot < 0")
+			return
+		}
+		memoryReserveMB = v
+	}
+
+	var serverResource = serverResource.NewServerResource(serverResource.Options{
+		OrphanPolicy:    orphanPolicy,
+		JavaDirs:        javaDirs,
+		MemoryReserveMB: memoryReserveMB,
+	})
 	var serverHandler = serverHandler.NewServerHandler(serverResource)
 	var router = NewRouter(serverHandler)
 
@@ -38,8 +38,10 @@ func NewRouter(sh serverHandler.ServerHandlerItf) *chi.Mux {
 
 	// get all servers data
 	router.Method(http.MethodGet, "/servers", httpHandler(sh.GetAllServerHandler))
-	// create new server
+	// create new server, from a template when given
 	router.Method(http.MethodPost, "/servers/create", httpHandler(sh.CreateServerHandler))
+	// clone server into a new one
+	router.Method(http.MethodPost, "/server/{id}/clone", httpHandler(sh.CloneServerHandler))
 	// delete server
 	router.Method(http.MethodDelete, "/server/{id}/delete", httpHandler(sh.DeleteServerHandler))
 	// agree eula
@@ -48,10 +50,64 @@ func NewRouter(sh serverHandler.ServerHandlerItf) *chi.Mux {
 	router.Method(http.MethodPatch, "/server/{id}/start", httpHandler(sh.StartServerHandler))
 	// stop server
 	router.Method(http.MethodPatch, "/server/{id}/stop", httpHandler(sh.StopServerHandler))
+	// restart server with its stored config
+	router.Method(http.MethodPatch, "/server/{id}/restart", httpHandler(sh.RestartServerHandler))
 	// get current server console status
 	router.Method(http.MethodGet, "/server/{id}/console", httpHandler(sh.GetServerConsoleHandler))
 	// add command to console
 	router.Method(http.MethodPost, "/server/{id}/console/execute", httpHandler(sh.AddServerConsoleHandler))
+	// stream console over sse or websocket
+	router.Method(http.MethodGet, "/server/{id}/console/stream", httpHandler(sh.StreamServerConsoleHandler))
+	// get parsed console logs
+	router.Method(http.MethodGet, "/server/{id}/logs", httpHandler(sh.GetServerLogsHandler))
+	// get full query stat
+	router.Method(http.MethodGet, "/server/{id}/query", httpHandler(sh.QueryServerHandler))
+	// get process metrics with their recent history
+	router.Method(http.MethodGet, "/server/{id}/metrics", httpHandler(sh.GetServerMetricsHandler))
+	// upload world archive
+	router.Method(http.MethodPut, "/server/{id}/world/upload", httpHandler(sh.UploadServerWorldHandler))
+	// back up server worlds
+	router.Method(http.MethodPost, "/server/{id}/backups", httpHandler(sh.CreateServerBackupHandler))
+	// get server backups
+	router.Method(http.MethodGet, "/server/{id}/backups", httpHandler(sh.GetAllServerBackupHandler))
+	// delete server backup
+	router.Method(http.MethodDelete, "/server/{id}/backups/{backupID}/delete", httpHandler(sh.DeleteServerBackupHandler))
+	// restore server worlds from backup
+	router.Method(http.MethodPost, "/server/{id}/backups/{backupID}/restore", httpHandler(sh.RestoreServerBackupHandler))
+	// get server.properties
+	router.Method(http.MethodGet, "/server/{id}/properties", httpHandler(sh.GetServerPropertiesHandler))
+	// update server.properties
+	router.Method(http.MethodPatch, "/server/{id}/properties", httpHandler(sh.UpdateServerPropertiesHandler))
+	// get server config
+	router.Method(http.MethodGet, "/server/{id}/config", httpHandler(sh.GetServerConfigHandler))
+	// update server config
+	router.Method(http.MethodPatch, "/server/{id}/config", httpHandler(sh.UpdateServerConfigHandler))
+	// get all jars of the catalog
+	router.Method(http.MethodGet, "/jars", httpHandler(sh.GetAllJarHandler))
+	// get jar details and checksum
+	router.Method(http.MethodGet, "/jar/{name}", httpHandler(sh.GetJarHandler))
+	// upload jar as raw request body or multipart form
+	router.Method(http.MethodPut, "/jar/{name}/upload", httpHandler(sh.UploadJarHandler))
+	// delete jar
+	router.Method(http.MethodDelete, "/jar/{name}/delete", httpHandler(sh.DeleteJarHandler))
+	// get all server templates
+	router.Method(http.MethodGet, "/templates", httpHandler(sh.GetAllTemplateHandler))
+	// get server template
+	router.Method(http.MethodGet, "/template/{name}", httpHandler(sh.GetTemplateHandler))
+	// create or replace server template
+	router.Method(http.MethodPut, "/template/{name}", httpHandler(sh.PutTemplateHandler))
+	// delete server template
+	router.Method(http.MethodDelete, "/template/{name}/delete", httpHandler(sh.DeleteTemplateHandler))
+	// upload seed world archive of server template
+	router.Method(http.MethodPut, "/template/{name}/world/upload", httpHandler(sh.UploadTemplateWorldHandler))
+	// upload plugins archive of server template
+	router.Method(http.MethodPut, "/template/{name}/plugins/upload", httpHandler(sh.UploadTemplatePluginsHandler))
+	// get jvm launch profiles
+	router.Method(http.MethodGet, "/launch-profiles", httpHandler(sh.GetAllLaunchProfileHandler))
+	// get discovered java runtimes
+	router.Method(http.MethodGet, "/runtimes", httpHandler(sh.GetAllRuntimeHandler))
+	// get host capacity, committed and free memory
+	router.Method(http.MethodGet, "/host", httpHandler(sh.GetHostHandler))
 
 	return router
 }
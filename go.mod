@@ -6,6 +6,7 @@ require (
 	github.com/fatih/color v1.13.0
 	github.com/go-chi/chi v1.5.4
 	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.5.0
 )
 
 require (
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+func (sh *serverHandler) CreateServerBackupHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.CreateServerBackupResource(id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) GetAllServerBackupHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.GetAllServerBackupResource(id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) DeleteServerBackupHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse backup id
+	backupID := chi.URLParam(r, "backupID")
+	if backupID == "" {
+		return errors.New("backup id is required")
+	}
+
+	if err := sh.Resource.DeleteServerBackupResource(id, backupID); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: "backup successfully deleted",
+	})
+}
+
+func (sh *serverHandler) RestoreServerBackupHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse backup id
+	backupID := chi.URLParam(r, "backupID")
+	if backupID == "" {
+		return errors.New("backup id is required")
+	}
+
+	// parse stop
+	var isStopping bool
+	if sStop := r.FormValue("stop"); sStop != "" {
+		v, err := strconv.ParseBool(sStop)
+		if err != nil {
+			return err
+		}
+		isStopping = v
+	}
+
+	res, err := sh.Resource.RestoreServerBackupResource(id, backupID, isStopping)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// StreamServerConsoleHandler tails the console over websocket when asked to upgrade, otherwise over server-sent events
+func (sh *serverHandler) StreamServerConsoleHandler(w http.ResponseWriter, r *http.Request) error {
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse backlog
+	var backlog int
+	if sBacklog := r.URL.Query().Get("backlog"); sBacklog != "" {
+		v, err := strconv.Atoi(sBacklog)
+		if err != nil {
+			return err
+		}
+		if v < 0 {
+			return errors.New("backlog cannot < 0")
+		}
+		backlog = v
+	}
+
+	// parse mode
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = "sse"
+		if websocket.IsWebSocketUpgrade(r) {
+			mode = "ws"
+		}
+	}
+	if mode != "sse" && mode != "ws" {
+		return errors.New("mode must be sse or ws")
+	}
+
+	sub, err := sh.Resource.SubscribeServerConsoleResource(id, backlog)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	if mode == "ws" {
+		return sh.streamConsoleWebSocket(w, r, id, sub)
+	}
+
+	return streamConsoleSSE(w, r, sub)
+}
+
+func streamConsoleSSE(w http.ResponseWriter, r *http.Request, sub *pkg.Subscription) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming is not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, line := range sub.Backlog {
+		fmt.Fprintf(w, "data: %v\n\n", line)
+	}
+	flusher.Flush()
+
+	// keep proxies from closing an idle stream
+	ticker := time.NewTicker(time.Second * 15)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case line, ok := <-sub.Lines:
+			if !ok {
+				fmt.Fprint(w, "event: end\ndata: console closed\n\n")
+				flusher.Flush()
+				return nil
+			}
+			fmt.Fprintf(w, "data: %v\n\n", line)
+		}
+		flusher.Flush()
+	}
+}
+
+// streamConsoleWebSocket sends console lines as text messages and executes every text message received as a command,
+// command replies and errors are sent back as text messages as well
+func (sh *serverHandler) streamConsoleWebSocket(w http.ResponseWriter, r *http.Request, id string, sub *pkg.Subscription) error {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already replied to the client
+		return nil
+	}
+	defer conn.Close()
+
+	replies := make(chan string, 16)
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			command := strings.TrimSpace(string(message))
+			if command == "" {
+				continue
+			}
+
+			// rcon replies do not show up in the console, hand them to the client directly
+			output, err := sh.Resource.AddServerConsoleResource(id, command)
+			if err != nil {
+				output = fmt.Sprintf("error: %v", err)
+			}
+			if output != "" {
+				select {
+				case replies <- output:
+				default:
+				}
+			}
+		}
+	}()
+
+	for _, line := range sub.Backlog {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+			return nil
+		}
+	}
+
+	for {
+		select {
+		case <-readDone:
+			return nil
+		case reply := <-replies:
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(reply)); err != nil {
+				return nil
+			}
+		case line, ok := <-sub.Lines:
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "console closed"))
+				return nil
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+				return nil
+			}
+		}
+	}
+}
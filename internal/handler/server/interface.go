@@ -5,10 +5,38 @@ import "net/http"
 type ServerHandlerItf interface {
 	GetAllServerHandler(http.ResponseWriter, *http.Request) error
 	CreateServerHandler(http.ResponseWriter, *http.Request) error
+	CloneServerHandler(http.ResponseWriter, *http.Request) error
 	DeleteServerHandler(http.ResponseWriter, *http.Request) error
 	AgreeEulaServerHandler(http.ResponseWriter, *http.Request) error
 	StartServerHandler(http.ResponseWriter, *http.Request) error
 	StopServerHandler(http.ResponseWriter, *http.Request) error
+	RestartServerHandler(http.ResponseWriter, *http.Request) error
 	GetServerConsoleHandler(http.ResponseWriter, *http.Request) error
 	AddServerConsoleHandler(http.ResponseWriter, *http.Request) error
+	StreamServerConsoleHandler(http.ResponseWriter, *http.Request) error
+	GetServerLogsHandler(http.ResponseWriter, *http.Request) error
+	QueryServerHandler(http.ResponseWriter, *http.Request) error
+	GetServerMetricsHandler(http.ResponseWriter, *http.Request) error
+	UploadServerWorldHandler(http.ResponseWriter, *http.Request) error
+	CreateServerBackupHandler(http.ResponseWriter, *http.Request) error
+	GetAllServerBackupHandler(http.ResponseWriter, *http.Request) error
+	DeleteServerBackupHandler(http.ResponseWriter, *http.Request) error
+	RestoreServerBackupHandler(http.ResponseWriter, *http.Request) error
+	GetServerPropertiesHandler(http.ResponseWriter, *http.Request) error
+	UpdateServerPropertiesHandler(http.ResponseWriter, *http.Request) error
+	GetAllJarHandler(http.ResponseWriter, *http.Request) error
+	GetJarHandler(http.ResponseWriter, *http.Request) error
+	UploadJarHandler(http.ResponseWriter, *http.Request) error
+	DeleteJarHandler(http.ResponseWriter, *http.Request) error
+	GetAllTemplateHandler(http.ResponseWriter, *http.Request) error
+	GetTemplateHandler(http.ResponseWriter, *http.Request) error
+	PutTemplateHandler(http.ResponseWriter, *http.Request) error
+	DeleteTemplateHandler(http.ResponseWriter, *http.Request) error
+	UploadTemplateWorldHandler(http.ResponseWriter, *http.Request) error
+	UploadTemplatePluginsHandler(http.ResponseWriter, *http.Request) error
+	GetAllLaunchProfileHandler(http.ResponseWriter, *http.Request) error
+	GetAllRuntimeHandler(http.ResponseWriter, *http.Request) error
+	GetHostHandler(http.ResponseWriter, *http.Request) error
+	GetServerConfigHandler(http.ResponseWriter, *http.Request) error
+	UpdateServerConfigHandler(http.ResponseWriter, *http.Request) error
 }
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+func (sh *serverHandler) GetAllJarHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	res, err := sh.Resource.GetAllJarResource()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) GetJarHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	res, err := sh.Resource.GetJarResource(name)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+// UploadJarHandler stores the raw request body or the file of a multipart form as the jar
+func (sh *serverHandler) UploadJarHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	// parse upload
+	body, req, err := parseUpload(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.UploadJarResource(name, body, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) DeleteJarHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	if err := sh.Resource.DeleteJarResource(name); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: "jar successfully deleted",
+	})
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+func (sh *serverHandler) GetAllLaunchProfileHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	res, err := sh.Resource.GetAllLaunchProfileResource()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
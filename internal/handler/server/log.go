@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+var logTimeRegex = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}$`)
+
+func (sh *serverHandler) GetServerLogsHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	filter := model.LogFilter{
+		Thread: query.Get("thread"),
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+		Limit:  100,
+	}
+
+	// parse level
+	if sLevel := query.Get("level"); sLevel != "" {
+		filter.Levels = strings.Split(sLevel, ",")
+	}
+
+	// parse time range
+	if filter.From != "" && !logTimeRegex.MatchString(filter.From) {
+		return errors.New("from must be HH:MM:SS")
+	}
+	if filter.To != "" && !logTimeRegex.MatchString(filter.To) {
+		return errors.New("to must be HH:MM:SS")
+	}
+
+	// parse regex
+	if sRegex := query.Get("regex"); sRegex != "" {
+		regex, err := regexp.Compile(sRegex)
+		if err != nil {
+			return err
+		}
+		filter.Regex = regex
+	}
+
+	// parse cursor
+	if sCursor := query.Get("cursor"); sCursor != "" {
+		cursor, err := strconv.Atoi(sCursor)
+		if err != nil {
+			return err
+		}
+		if cursor < 0 {
+			return errors.New("cursor cannot < 0")
+		}
+		filter.Cursor = cursor
+	}
+
+	// parse limit
+	if sLimit := query.Get("limit"); sLimit != "" {
+		limit, err := strconv.Atoi(sLimit)
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return errors.New("limit cannot <= 0")
+		}
+		if limit > 1000 {
+			return errors.New("limit cannot > 1000")
+		}
+		filter.Limit = limit
+	}
+
+	res, err := sh.Resource.GetServerLogsResource(id, filter)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
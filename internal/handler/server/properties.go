@@ -0,0 +1,104 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+func (sh *serverHandler) GetServerPropertiesHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.GetServerPropertiesResource(id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+// UpdateServerPropertiesHandler takes a json object of properties, values may be strings, numbers or booleans and
+// null removes the property
+func (sh *serverHandler) UpdateServerPropertiesHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse body
+	var body map[string]interface{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return errors.New("properties are required")
+	}
+
+	req := make(model.UpdateServerPropertiesRequest)
+	for key, value := range body {
+		var sValue string
+		switch v := value.(type) {
+		case nil:
+			req[key] = nil
+			continue
+		case string:
+			sValue = v
+		case json.Number:
+			sValue = v.String()
+		case bool:
+			sValue = strconv.FormatBool(v)
+		default:
+			return fmt.Errorf("property %v must be a string, number, boolean or null", key)
+		}
+		req[key] = &sValue
+	}
+
+	res, err := sh.Resource.UpdateServerPropertiesResource(id, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Bearaujus/minecraft-server-api/internal/model"
 	"github.com/Bearaujus/minecraft-server-api/pkg"
@@ -21,7 +24,13 @@ func (sh *serverHandler) GetAllServerHandler(w http.ResponseWriter, r *http.Requ
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	modelServer, err := sh.Resource.GetAllServerResource()
+	// parse label selector, every server is listed when omitted
+	selector, err := model.ParseLabelSelector(r.FormValue("selector"))
+	if err != nil {
+		return err
+	}
+
+	modelServer, err := sh.Resource.GetAllServerResource(selector)
 	if err != nil {
 		return err
 	}
@@ -29,26 +38,67 @@ func (sh *serverHandler) GetAllServerHandler(w http.ResponseWriter, r *http.Requ
 	outputRes := make([]model.GetAllServerResponse, 0)
 	for k, v := range modelServer {
 		resItem := model.GetAllServerResponse{
-			ServerID: k,
+			ServerID:       k,
+			Name:           v.Config.Name,
+			Description:    v.Config.Description,
+			Owner:          v.Config.Owner,
+			Labels:         v.Config.Labels,
+			Status:         string(v.Status),
+			RestartCount:   v.RestartCount,
+			LastExitCode:   v.LastExitCode,
+			LastExitReason: v.LastExitReason,
+		}
+		if !v.LastExitAt.IsZero() {
+			resItem.LastExitAt = v.LastExitAt.Format(time.RFC3339)
+		}
+		if !v.NextRestartAt.IsZero() {
+			resItem.NextRestartAt = v.NextRestartAt.Format(time.RFC3339)
+		}
+		resItem.LastStopOutcome = string(v.LastStopOutcome)
+		resItem.IsRestarting = v.IsRestarting
+		resItem.IsRestartRequired = v.IsRestartRequired
+		resItem.IsBackingUp = v.IsBackingUp
+		if !v.LastBackupAt.IsZero() {
+			resItem.LastBackupAt = v.LastBackupAt.Format(time.RFC3339)
 		}
 
-		if v == nil {
-			resItem.Status = "stopped"
-			resItem.LastError = resItem.GetLastError(k)
-		} else if v.IsAttemptedToStop {
-			resItem.Status = "stopping"
-		} else if v.IsAttemptedToStart {
-			resItem.Status = "starting"
-		} else {
-			resItem.Status = "running"
+		if metrics, err := sh.Resource.GetServerMetricsResource(k); err == nil {
+			resItem.Metrics = metrics.Current
+		}
+
+		switch v.Status {
+		case model.ServerStatusRunning:
 			resItem.Address = fmt.Sprintf("localhost:%v", v.Port)
 			resItem.OnlineMode = resItem.IsRunningOnlineMode(k)
 			resItem.WorldName = resItem.GetUsedWorldName(k)
+		case model.ServerStatusCreated, model.ServerStatusStopped, model.ServerStatusCrashed:
+			resItem.LastError = resItem.GetLastError(k)
 		}
 
 		outputRes = append(outputRes, resItem)
 	}
 
+	// probe every running server at once so one slow server doesn't hold the listing
+	var wg sync.WaitGroup
+	for i := range outputRes {
+		if outputRes[i].Status != string(model.ServerStatusRunning) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(resItem *model.GetAllServerResponse) {
+			defer wg.Done()
+
+			ping, err := sh.Resource.PingServerResource(resItem.ServerID)
+			if err != nil {
+				resItem.LastError = fmt.Sprintf("server does not answer status requests: %v", err)
+				return
+			}
+			resItem.Ping = ping
+		}(&outputRes[i])
+	}
+	wg.Wait()
+
 	sort.Slice(outputRes, func(i, j int) bool {
 		return outputRes[i].ServerID < outputRes[j].ServerID
 	})
@@ -71,7 +121,10 @@ func (sh *serverHandler) CreateServerHandler(w http.ResponseWriter, r *http.Requ
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	res, err := sh.Resource.CreateServerResource()
+	// parse template, an empty server is made when omitted
+	template := r.FormValue("template")
+
+	res, err := sh.Resource.CreateServerResource(template)
 	if err != nil {
 		return err
 	}
@@ -88,19 +141,38 @@ func (sh *serverHandler) CreateServerHandler(w http.ResponseWriter, r *http.Requ
 	})
 }
 
-func (sh *serverHandler) DeleteServerHandler(w http.ResponseWriter, r *http.Request) error {
+func (sh *serverHandler) CloneServerHandler(w http.ResponseWriter, r *http.Request) error {
 	timer := pkg.StartNewTimer()
 	defer func() {
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
 	}
 
-	err := sh.Resource.DeleteServerResource(id)
+	// parse name, falls back to the source name with a -copy suffix when omitted
+	name := r.FormValue("name")
+
+	// parse port, a free port is picked when omitted
+	var port int
+	if sPort := r.FormValue("port"); sPort != "" {
+		v, err := strconv.Atoi(sPort)
+		if err != nil {
+			return err
+		}
+		if v < model.MIN_SERVER_PORT {
+			return errors.New("port cannot <= 25000")
+		}
+		if v > model.MAX_SERVER_PORT {
+			return errors.New("port cannot >= 30000")
+		}
+		port = v
+	}
+
+	res, err := sh.Resource.CloneServerResource(id, name, port)
 	if err != nil {
 		return err
 	}
@@ -113,23 +185,23 @@ func (sh *serverHandler) DeleteServerHandler(w http.ResponseWriter, r *http.Requ
 			IsSuccess:   true,
 			Messages:    nil,
 		},
-		Data: "server successfully deleted",
+		Data: res,
 	})
 }
 
-func (sh *serverHandler) AgreeEulaServerHandler(w http.ResponseWriter, r *http.Request) error {
+func (sh *serverHandler) DeleteServerHandler(w http.ResponseWriter, r *http.Request) error {
 	timer := pkg.StartNewTimer()
 	defer func() {
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
 	}
 
-	err := sh.Resource.AgreeEulaServerResource(id)
+	err = sh.Resource.DeleteServerResource(id)
 	if err != nil {
 		return err
 	}
@@ -142,49 +214,78 @@ func (sh *serverHandler) AgreeEulaServerHandler(w http.ResponseWriter, r *http.R
 			IsSuccess:   true,
 			Messages:    nil,
 		},
-		Data: "eula set to agree",
+		Data: "server successfully deleted",
 	})
 }
 
-func (sh *serverHandler) StartServerHandler(w http.ResponseWriter, r *http.Request) error {
+func (sh *serverHandler) AgreeEulaServerHandler(w http.ResponseWriter, r *http.Request) error {
 	timer := pkg.StartNewTimer()
 	defer func() {
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
 	}
 
-	// parse ram
-	sRamGB := r.FormValue("ram_gb")
-	if sRamGB == "" {
-		return errors.New("ram_gb is required")
-	}
-	ramGB, err := strconv.Atoi(sRamGB)
+	err = sh.Resource.AgreeEulaServerResource(id)
 	if err != nil {
 		return err
 	}
-	if ramGB <= 0 {
-		return errors.New("ram_gb cannot <= 0")
-	}
 
-	// parse port
-	sPort := r.FormValue("port")
-	if sPort == "" {
-		return errors.New("port is required")
-	}
-	port, err := strconv.Atoi(sPort)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: "eula set to agree",
+	})
+}
+
+func (sh *serverHandler) StartServerHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
 	if err != nil {
 		return err
 	}
-	if port < 25000 {
-		return errors.New("port cannot <= 25000")
+
+	// parse ram, falls back to the stored config when omitted
+	var ramGB int
+	if sRamGB := r.FormValue("ram_gb"); sRamGB != "" {
+		v, err := strconv.Atoi(sRamGB)
+		if err != nil {
+			return err
+		}
+		if v <= 0 {
+			return errors.New("ram_gb cannot <= 0")
+		}
+		ramGB = v
 	}
-	if port > 30000 {
-		return errors.New("port cannot >= 30000")
+
+	// parse port, falls back to the stored config when omitted and to a free port of the pool when neither is set
+	var port int
+	if sPort := r.FormValue("port"); sPort != "" {
+		v, err := strconv.Atoi(sPort)
+		if err != nil {
+			return err
+		}
+		if v < model.MIN_SERVER_PORT {
+			return errors.New("port cannot <= 25000")
+		}
+		if v > model.MAX_SERVER_PORT {
+			return errors.New("port cannot >= 30000")
+		}
+		port = v
 	}
 
 	// parse world
@@ -212,16 +313,49 @@ func (sh *serverHandler) StopServerHandler(w http.ResponseWriter, r *http.Reques
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse stop policy overrides
+	var req model.StopServerRequest
+	for key, dst := range map[string]**int{
+		"grace_seconds": &req.GraceSeconds,
+		"term_seconds":  &req.TermSeconds,
+		"warn_seconds":  &req.WarnSeconds,
+	} {
+		sValue := r.FormValue(key)
+		if sValue == "" {
+			continue
+		}
+		value, err := strconv.Atoi(sValue)
+		if err != nil {
+			return err
+		}
+		*dst = &value
 	}
 
-	if err := sh.Resource.StopServerResource(id); err != nil {
+	// parse wait
+	if sWait := r.FormValue("wait"); sWait != "" {
+		wait, err := strconv.ParseBool(sWait)
+		if err != nil {
+			return err
+		}
+		req.Wait = wait
+	}
+
+	outcome, err := sh.Resource.StopServerResource(id, req)
+	if err != nil {
 		return err
 	}
 
+	var res interface{} = "attempted to stop"
+	if outcome != "" {
+		res = fmt.Sprintf("server %v", outcome)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(model.Response{
@@ -230,7 +364,35 @@ func (sh *serverHandler) StopServerHandler(w http.ResponseWriter, r *http.Reques
 			IsSuccess:   true,
 			Messages:    nil,
 		},
-		Data: "attempted to stop",
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) RestartServerHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	if err := sh.Resource.RestartServerResource(id); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: "attempted to restart",
 	})
 }
 
@@ -240,10 +402,10 @@ func (sh *serverHandler) GetServerConsoleHandler(w http.ResponseWriter, r *http.
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
 	}
 
 	res, err := sh.Resource.GetServerConsoleResource(id)
@@ -282,10 +444,10 @@ func (sh *serverHandler) AddServerConsoleHandler(w http.ResponseWriter, r *http.
 		w.Header().Add("time_elapsed", timer.SinceStringInMS())
 	}()
 
-	// parse id
-	id := chi.URLParam(r, "id")
-	if id == "" {
-		return errors.New("id is required")
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
 	}
 
 	// parse command
@@ -294,7 +456,82 @@ func (sh *serverHandler) AddServerConsoleHandler(w http.ResponseWriter, r *http.
 		return errors.New("command is required")
 	}
 
-	if err := sh.Resource.AddServerConsoleResource(id, command); err != nil {
+	// parse wait, the command output is only collected when waiting for it
+	var wait time.Duration
+	if sWaitMS := r.FormValue("wait_ms"); sWaitMS != "" {
+		waitMS, err := strconv.Atoi(sWaitMS)
+		if err != nil {
+			return err
+		}
+		if waitMS <= 0 {
+			return errors.New("wait_ms cannot <= 0")
+		}
+		if waitMS > 30000 {
+			return errors.New("wait_ms cannot > 30000")
+		}
+		wait = time.Duration(waitMS) * time.Millisecond
+	}
+
+	// parse until
+	var until *regexp.Regexp
+	if sUntil := r.FormValue("until"); sUntil != "" {
+		v, err := regexp.Compile(sUntil)
+		if err != nil {
+			return err
+		}
+		until = v
+		if wait == 0 {
+			wait = time.Second * 5
+		}
+	}
+
+	var res interface{} = "command executed"
+	if wait > 0 {
+		output, err := sh.Resource.ExecuteServerConsoleResource(id, command, wait, until)
+		if err != nil {
+			return err
+		}
+		res = output
+	} else {
+		output, err := sh.Resource.AddServerConsoleResource(id, command)
+		if err != nil {
+			return err
+		}
+		if output != "" {
+			// nothing was matched, rcon simply answered with the output
+			res = model.ExecuteCommandResponse{
+				Command: command,
+				Output:  strings.Split(strings.TrimRight(output, "\n"), "\n"),
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) GetServerConfigHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.GetServerConfigResource(id)
+	if err != nil {
 		return err
 	}
 
@@ -306,6 +543,99 @@ func (sh *serverHandler) AddServerConsoleHandler(w http.ResponseWriter, r *http.
 			IsSuccess:   true,
 			Messages:    nil,
 		},
-		Data: "command executed",
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) UpdateServerConfigHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse body
+	var req model.UpdateServerConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.UpdateServerConfigResource(id, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) QueryServerHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.QueryServerResource(id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) GetServerMetricsHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.GetServerMetricsResource(id)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
 	})
 }
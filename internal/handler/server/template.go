@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+func (sh *serverHandler) GetAllTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	res, err := sh.Resource.GetAllTemplateResource()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) GetTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	res, err := sh.Resource.GetTemplateResource(name)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) PutTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	// parse body
+	var req model.Template
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.PutTemplateResource(name, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+func (sh *serverHandler) DeleteTemplateHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	if err := sh.Resource.DeleteTemplateResource(name); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: "template successfully deleted",
+	})
+}
+
+// UploadTemplateWorldHandler stores the zip or tar.gz archive of the raw request body or multipart form as the seed
+// world of the template
+func (sh *serverHandler) UploadTemplateWorldHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	// parse upload
+	body, req, err := parseUpload(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.UploadTemplateWorldResource(name, body, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
+
+// UploadTemplatePluginsHandler stores the zip or tar.gz archive of the raw request body or multipart form as the
+// plugins folder of the template
+func (sh *serverHandler) UploadTemplatePluginsHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse name
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		return errors.New("name is required")
+	}
+
+	// parse upload
+	body, req, err := parseUpload(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.UploadTemplatePluginsResource(name, body, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+
+	"github.com/go-chi/chi"
+)
+
+// parseUpload returns the uploaded content, either the raw request body or the `file` part of a multipart form, the
+// sha256 and overwrite options come from the query or from form fields sent ahead of the file
+func parseUpload(r *http.Request) (io.Reader, model.UploadRequest, error) {
+	var req model.UploadRequest
+
+	values := map[string]string{
+		"sha256":    r.URL.Query().Get("sha256"),
+		"overwrite": r.URL.Query().Get("overwrite"),
+	}
+
+	var body io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return nil, req, err
+		}
+
+		body = nil
+		for body == nil {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return nil, req, errors.New("file is required")
+			}
+			if err != nil {
+				return nil, req, err
+			}
+
+			name := part.FormName()
+			if name == "file" {
+				// the file streams straight from the request, it has to be the last part read
+				body = part
+				continue
+			}
+
+			if _, ok := values[name]; ok {
+				v, err := ioutil.ReadAll(io.LimitReader(part, 128))
+				if err != nil {
+					return nil, req, err
+				}
+				values[name] = strings.TrimSpace(string(v))
+			}
+		}
+	}
+
+	req.SHA256 = values["sha256"]
+	if sOverwrite := values["overwrite"]; sOverwrite != "" {
+		v, err := strconv.ParseBool(sOverwrite)
+		if err != nil {
+			return nil, req, err
+		}
+		req.IsOverwrite = v
+	}
+
+	return body, req, nil
+}
+
+// UploadServerWorldHandler unpacks a zip or tar.gz world archive into the server, the world name defaults to the
+// configured world
+func (sh *serverHandler) UploadServerWorldHandler(w http.ResponseWriter, r *http.Request) error {
+	timer := pkg.StartNewTimer()
+	defer func() {
+		w.Header().Add("time_elapsed", timer.SinceStringInMS())
+	}()
+
+	// parse id, the server name stands for it as well
+	id, err := sh.Resource.ResolveServerIDResource(chi.URLParam(r, "id"))
+	if err != nil {
+		return err
+	}
+
+	// parse world name, FormValue would consume a multipart body
+	worldName := r.URL.Query().Get("world_name")
+
+	// parse upload
+	body, req, err := parseUpload(r)
+	if err != nil {
+		return err
+	}
+
+	res, err := sh.Resource.UploadServerWorldResource(id, worldName, body, req)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(model.Response{
+		Header: model.ResponseHeader{
+			ProcessTime: timer.SinceStringInMS(),
+			IsSuccess:   true,
+			Messages:    nil,
+		},
+		Data: res,
+	})
+}
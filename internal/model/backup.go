@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	// FILE_BACKUP_MANIFEST is written at the root of every backup archive
+	FILE_BACKUP_MANIFEST = "msa-backup.json"
+
+	BACKUP_ARCHIVE_EXT  = ".tar.gz"
+	BACKUP_MANIFEST_EXT = ".json"
+)
+
+var backupIDRegex = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+func ValidateBackupID(id string) error {
+	if !backupIDRegex.MatchString(id) {
+		return errors.New("backup id is malformed")
+	}
+
+	return nil
+}
+
+type BackupTrigger string
+
+const (
+	BackupTriggerManual    BackupTrigger = "manual"
+	BackupTriggerScheduled BackupTrigger = "scheduled"
+	// BackupTriggerPreRestore backups hold the worlds a restore replaced, retention leaves them alone
+	BackupTriggerPreRestore BackupTrigger = "pre-restore"
+)
+
+// Backup describes a world archive, it is stored inside the archive and next to it with Size and SHA256 filled
+type Backup struct {
+	ID        string        `json:"id"`
+	ServerID  string        `json:"server_id"`
+	CreatedAt time.Time     `json:"created_at"`
+	Trigger   BackupTrigger `json:"trigger"`
+	// Worlds are the world folders inside the archive, relative to the server folder
+	Worlds  []string `json:"worlds"`
+	Jar     string   `json:"jar"`
+	Version string   `json:"version,omitempty"`
+	// IsOnline tells the backup was taken while the server was running, with saving paused
+	IsOnline bool `json:"is_online"`
+
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// BackupPolicy schedules backups of running servers and decides which backups to keep, every backup of the latest
+// KeepLast ones, of the latest KeepDaily days and of the latest KeepWeekly weeks is kept
+type BackupPolicy struct {
+	// IntervalMinutes between scheduled backups, 0 disables them
+	IntervalMinutes int `json:"interval_minutes"`
+	KeepLast        int `json:"keep_last"`
+	KeepDaily       int `json:"keep_daily"`
+	KeepWeekly      int `json:"keep_weekly"`
+}
+
+func NewDefaultBackupPolicy() BackupPolicy {
+	return BackupPolicy{
+		IntervalMinutes: 0,
+		KeepLast:        10,
+		KeepDaily:       7,
+		KeepWeekly:      4,
+	}
+}
+
+func (bp BackupPolicy) Validate() error {
+	if bp.IntervalMinutes < 0 {
+		return errors.New("interval_minutes cannot < 0")
+	}
+	if bp.IntervalMinutes != 0 && bp.IntervalMinutes < 5 {
+		return errors.New("interval_minutes cannot < 5")
+	}
+	if bp.KeepLast < 0 || bp.KeepDaily < 0 || bp.KeepWeekly < 0 {
+		return errors.New("keep_last, keep_daily and keep_weekly cannot < 0")
+	}
+
+	return nil
+}
+
+// isKeepingAll tells the policy has no retention, an empty policy never deletes anything
+func (bp BackupPolicy) isKeepingAll() bool {
+	return bp.KeepLast == 0 && bp.KeepDaily == 0 && bp.KeepWeekly == 0
+}
+
+// GetExpiredBackups returns the backups the policy no longer keeps, the newest backup of a day or week stands for it
+func (bp BackupPolicy) GetExpiredBackups(backups []Backup) []Backup {
+	if bp.isKeepingAll() {
+		return nil
+	}
+
+	sorted := append([]Backup{}, backups...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	keep := make(map[string]bool)
+	for i := 0; i < len(sorted) && i < bp.KeepLast; i++ {
+		keep[sorted[i].ID] = true
+	}
+
+	keepBuckets := func(limit int, bucket func(time.Time) string) {
+		seen := make(map[string]bool)
+		for _, backup := range sorted {
+			if len(seen) >= limit {
+				return
+			}
+
+			b := bucket(backup.CreatedAt)
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+			keep[backup.ID] = true
+		}
+	}
+	keepBuckets(bp.KeepDaily, func(t time.Time) string {
+		return t.Local().Format("2006-01-02")
+	})
+	keepBuckets(bp.KeepWeekly, func(t time.Time) string {
+		year, week := t.Local().ISOWeek()
+		return fmt.Sprintf("%v-%02d", year, week)
+	})
+
+	var res []Backup
+	for _, backup := range sorted {
+		if !keep[backup.ID] {
+			res = append(res, backup)
+		}
+	}
+
+	return res
+}
+
+type RestoreBackupResponse struct {
+	Backup Backup `json:"backup"`
+	// SafetyBackupID is the backup of the worlds the restore replaced, restoring it undoes the restore
+	SafetyBackupID string `json:"safety_backup_id,omitempty"`
+}
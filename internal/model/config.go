@@ -0,0 +1,446 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FILE_SERVER_CONFIG = "msa.json"
+
+	MIN_SERVER_PORT = 25000
+	MAX_SERVER_PORT = 30000
+
+	DEFAULT_SERVER_JAR = "server-1.19.2.jar"
+
+	FILE_SERVER_PROPERTIES = "server.properties"
+
+	// RCON_PORT_OFFSET derives the rcon port from the server port when none is configured
+	RCON_PORT_OFFSET = 10000
+)
+
+// DEFAULT_JVM_FLAGS are aikar's G1GC flags, see https://mcflags.emc.gs
+var DEFAULT_JVM_FLAGS = []string{
+	"-XX:+UseG1GC",
+	"-XX:+ParallelRefProcEnabled",
+	"-XX:MaxGCPauseMillis=200",
+	"-XX:+UnlockExperimentalVMOptions",
+	"-XX:+DisableExplicitGC",
+	"-XX:+AlwaysPreTouch",
+	"-XX:G1NewSizePercent=30",
+	"-XX:G1MaxNewSizePercent=40",
+	"-XX:G1HeapRegionSize=8M",
+	"-XX:G1ReservePercent=20",
+	"-XX:G1HeapWastePercent=5",
+	"-XX:G1MixedGCCountTarget=4",
+	"-XX:InitiatingHeapOccupancyPercent=15",
+	"-XX:G1MixedGCLiveThresholdPercent=90",
+	"-XX:G1RSetUpdatingPauseTimePercent=5",
+	"-XX:SurvivorRatio=32",
+	"-XX:+PerfDisableSharedMem",
+	"-XX:MaxTenuringThreshold=1",
+	"-Dusing.aikars.flags=https://mcflags.emc.gs",
+	"-Daikars.new.flags=true",
+}
+
+type RestartMode string
+
+const (
+	RestartModeNever     RestartMode = "never"
+	RestartModeOnFailure RestartMode = "on-failure"
+	RestartModeAlways    RestartMode = "always"
+)
+
+// RestartPolicy decides whether the supervisor brings a server back after its process exits on its own
+type RestartPolicy struct {
+	Mode RestartMode `json:"mode"`
+	// MaxRetries is the number of automatic restarts allowed since the last manual start, 0 means unlimited
+	MaxRetries     int `json:"max_retries"`
+	BackoffSeconds int `json:"backoff_seconds"`
+	// MaxBackoffSeconds caps the doubling backoff between consecutive restarts
+	MaxBackoffSeconds int `json:"max_backoff_seconds"`
+}
+
+func NewDefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		Mode:              RestartModeNever,
+		MaxRetries:        3,
+		BackoffSeconds:    5,
+		MaxBackoffSeconds: 300,
+	}
+}
+
+func (rp RestartPolicy) Validate() error {
+	switch rp.Mode {
+	case RestartModeNever, RestartModeOnFailure, RestartModeAlways:
+	default:
+		return fmt.Errorf("unknown restart mode %v", rp.Mode)
+	}
+
+	if rp.MaxRetries < 0 {
+		return errors.New("max_retries cannot < 0")
+	}
+	if rp.BackoffSeconds <= 0 {
+		return errors.New("backoff_seconds cannot <= 0")
+	}
+	if rp.MaxBackoffSeconds < rp.BackoffSeconds {
+		return errors.New("max_backoff_seconds cannot < backoff_seconds")
+	}
+
+	return nil
+}
+
+// ShouldRestart reports whether a process that exited with the given code deserves another attempt
+func (rp RestartPolicy) ShouldRestart(exitCode, restartCount int) bool {
+	if rp.MaxRetries > 0 && restartCount >= rp.MaxRetries {
+		return false
+	}
+
+	switch rp.Mode {
+	case RestartModeAlways:
+		return true
+	case RestartModeOnFailure:
+		return exitCode != 0
+	}
+
+	return false
+}
+
+// Backoff doubles the delay on every consecutive restart
+func (rp RestartPolicy) Backoff(restartCount int) time.Duration {
+	res := time.Duration(rp.BackoffSeconds) * time.Second
+	max := time.Duration(rp.MaxBackoffSeconds) * time.Second
+	for i := 0; i < restartCount && res < max; i++ {
+		res *= 2
+	}
+	if res > max {
+		res = max
+	}
+
+	return res
+}
+
+// StopPolicy bounds how long a stop may take before it escalates to sigterm and then sigkill
+type StopPolicy struct {
+	// GraceSeconds is the time given to the stop command before sending sigterm
+	GraceSeconds int `json:"grace_seconds"`
+	// TermSeconds is the time given to sigterm before sending sigkill
+	TermSeconds int `json:"term_seconds"`
+	// WarnSeconds announces a countdown in game before the stop command, 0 disables it
+	WarnSeconds int `json:"warn_seconds"`
+}
+
+func NewDefaultStopPolicy() StopPolicy {
+	return StopPolicy{
+		GraceSeconds: 60,
+		TermSeconds:  30,
+		WarnSeconds:  0,
+	}
+}
+
+func (sp StopPolicy) Validate() error {
+	if sp.GraceSeconds <= 0 {
+		return errors.New("grace_seconds cannot <= 0")
+	}
+	if sp.TermSeconds <= 0 {
+		return errors.New("term_seconds cannot <= 0")
+	}
+	if sp.WarnSeconds < 0 {
+		return errors.New("warn_seconds cannot < 0")
+	}
+
+	return nil
+}
+
+// ServerConfig is the persisted definition of a server, stored as msa.json inside the server folder
+type ServerConfig struct {
+	// Name is unique among servers and can stand for the id in routes, see ValidateServerName
+	Name      string            `json:"name"`
+	Port      int               `json:"port"`
+	RamGB     int               `json:"ram_gb"`
+	Jar       string            `json:"jar"`
+	WorldName string            `json:"world_name"`
+	Metadata  map[string]string `json:"metadata"`
+
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	// Labels are free-form tags matched by the label selector of the server listing
+	Labels map[string]string `json:"labels"`
+
+	// JVMFlags replace the flags of the launch profile when set, configs written before profiles existed carry them
+	JVMFlags []string     `json:"jvm_flags,omitempty"`
+	Launch   LaunchConfig `json:"launch"`
+	// Runtime pins the id of a discovered java runtime, launch.java_path wins over it
+	Runtime string `json:"runtime,omitempty"`
+
+	RestartPolicy RestartPolicy `json:"restart_policy"`
+	StopPolicy    StopPolicy    `json:"stop_policy"`
+	BackupPolicy  BackupPolicy  `json:"backup_policy"`
+
+	// RconPort defaults to Port + RCON_PORT_OFFSET when 0
+	RconPort int `json:"rcon_port,omitempty"`
+	// QueryPort is an udp port, it defaults to Port when 0
+	QueryPort int `json:"query_port,omitempty"`
+}
+
+func (sc ServerConfig) GetRconPort() int {
+	if sc.RconPort != 0 {
+		return sc.RconPort
+	}
+
+	return sc.Port + RCON_PORT_OFFSET
+}
+
+func (sc ServerConfig) GetQueryPort() int {
+	if sc.QueryPort != 0 {
+		return sc.QueryPort
+	}
+
+	return sc.Port
+}
+
+// GetReservedPorts returns the game, rcon and query ports the server holds once launched, a server without a port
+// only reserves the rcon and query ports it sets explicitly
+func (sc ServerConfig) GetReservedPorts() []int {
+	var res []int
+	if sc.Port != 0 {
+		res = append(res, sc.Port, sc.GetRconPort())
+		if sc.GetQueryPort() != sc.Port {
+			res = append(res, sc.GetQueryPort())
+		}
+		return res
+	}
+
+	if sc.RconPort != 0 {
+		res = append(res, sc.RconPort)
+	}
+	if sc.QueryPort != 0 {
+		res = append(res, sc.QueryPort)
+	}
+
+	return res
+}
+
+// GetVersion is the minecraft version of the jar as far as its file name tells, empty when unknown
+func (sc ServerConfig) GetVersion() string {
+	return ParseVersion(sc.Jar)
+}
+
+func NewDefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Jar:           DEFAULT_SERVER_JAR,
+		Launch:        NewDefaultLaunchConfig(),
+		Metadata:      map[string]string{},
+		Labels:        map[string]string{},
+		RestartPolicy: NewDefaultRestartPolicy(),
+		StopPolicy:    NewDefaultStopPolicy(),
+		BackupPolicy:  NewDefaultBackupPolicy(),
+	}
+}
+
+// Validate checks that the config holds everything needed to launch the server
+func (sc ServerConfig) Validate() error {
+	if sc.RamGB <= 0 && sc.Launch.XmxMB == 0 {
+		return errors.New("ram_gb is required")
+	}
+
+	if sc.Port == 0 {
+		return errors.New("port is required")
+	}
+	if sc.Port < MIN_SERVER_PORT {
+		return errors.New("port cannot <= 25000")
+	}
+	if sc.Port > MAX_SERVER_PORT {
+		return errors.New("port cannot >= 30000")
+	}
+
+	if sc.WorldName != "" {
+		if err := ValidateWorldName(sc.WorldName); err != nil {
+			return err
+		}
+	}
+
+	if sc.Jar == "" {
+		return errors.New("jar is required")
+	}
+	if strings.ContainsAny(sc.Jar, `/\`) {
+		return errors.New("jar must be a file name inside the jar folder")
+	}
+
+	return sc.Launch.Validate()
+}
+
+// UpdateServerConfigRequest holds the fields of a config patch, nil fields are left untouched
+type UpdateServerConfigRequest struct {
+	Name      *string            `json:"name"`
+	Port      *int               `json:"port"`
+	RamGB     *int               `json:"ram_gb"`
+	Jar       *string            `json:"jar"`
+	JVMFlags  *[]string          `json:"jvm_flags"`
+	WorldName *string            `json:"world_name"`
+	Metadata  *map[string]string `json:"metadata"`
+
+	Description *string            `json:"description"`
+	Owner       *string            `json:"owner"`
+	Labels      *map[string]string `json:"labels"`
+
+	RestartPolicy *RestartPolicy `json:"restart_policy"`
+	StopPolicy    *StopPolicy    `json:"stop_policy"`
+	BackupPolicy  *BackupPolicy  `json:"backup_policy"`
+	RconPort      *int           `json:"rcon_port"`
+	QueryPort     *int           `json:"query_port"`
+	Launch        *LaunchConfig  `json:"launch"`
+	Runtime       *string        `json:"runtime"`
+
+	// Version picks the catalog jar of that minecraft version, it is resolved into Jar before Apply
+	Version *string `json:"version"`
+}
+
+func (req UpdateServerConfigRequest) Apply(sc ServerConfig) (ServerConfig, error) {
+	if req.Name != nil {
+		if err := ValidateServerName(*req.Name); err != nil {
+			return sc, err
+		}
+		sc.Name = *req.Name
+	}
+
+	if req.Port != nil {
+		if *req.Port < MIN_SERVER_PORT {
+			return sc, errors.New("port cannot <= 25000")
+		}
+		if *req.Port > MAX_SERVER_PORT {
+			return sc, errors.New("port cannot >= 30000")
+		}
+		sc.Port = *req.Port
+	}
+
+	if req.RamGB != nil {
+		if *req.RamGB <= 0 {
+			return sc, errors.New("ram_gb cannot <= 0")
+		}
+		sc.RamGB = *req.RamGB
+	}
+
+	if req.Jar != nil {
+		if err := ValidateJarName(*req.Jar); err != nil {
+			return sc, err
+		}
+		sc.Jar = *req.Jar
+	}
+
+	if req.Launch != nil {
+		if req.Launch.ExtraJVMFlags == nil {
+			req.Launch.ExtraJVMFlags = []string{}
+		}
+		if req.Launch.SystemProperties == nil {
+			req.Launch.SystemProperties = map[string]string{}
+		}
+		if req.Launch.ExtraServerArgs == nil {
+			req.Launch.ExtraServerArgs = []string{}
+		}
+		if err := req.Launch.Validate(); err != nil {
+			return sc, err
+		}
+		sc.Launch = *req.Launch
+		// picking a launch setup drops flags carried over from before profiles existed
+		sc.JVMFlags = nil
+	}
+
+	if req.Runtime != nil {
+		if strings.ContainsAny(*req.Runtime, `/\`) {
+			return sc, errors.New("runtime must be the id of a discovered runtime")
+		}
+		sc.Runtime = *req.Runtime
+	}
+
+	if req.JVMFlags != nil {
+		for _, flag := range *req.JVMFlags {
+			if err := ValidateJVMFlag(flag); err != nil {
+				return sc, err
+			}
+		}
+		sc.JVMFlags = *req.JVMFlags
+		if len(sc.JVMFlags) == 0 {
+			sc.JVMFlags = nil
+		}
+	}
+
+	if req.WorldName != nil {
+		if *req.WorldName != "" {
+			if err := ValidateWorldName(*req.WorldName); err != nil {
+				return sc, err
+			}
+		}
+		sc.WorldName = *req.WorldName
+	}
+
+	if req.Metadata != nil {
+		sc.Metadata = *req.Metadata
+	}
+
+	if req.Description != nil {
+		if len(*req.Description) > MAX_SERVER_DESCRIPTION_LENGTH {
+			return sc, fmt.Errorf("description cannot > %v characters", MAX_SERVER_DESCRIPTION_LENGTH)
+		}
+		sc.Description = *req.Description
+	}
+
+	if req.Owner != nil {
+		if len(*req.Owner) > MAX_SERVER_OWNER_LENGTH {
+			return sc, fmt.Errorf("owner cannot > %v characters", MAX_SERVER_OWNER_LENGTH)
+		}
+		if hasControlChar(*req.Owner) {
+			return sc, errors.New("owner cannot contain control characters")
+		}
+		sc.Owner = *req.Owner
+	}
+
+	if req.Labels != nil {
+		if err := ValidateLabels(*req.Labels); err != nil {
+			return sc, err
+		}
+		sc.Labels = *req.Labels
+		if sc.Labels == nil {
+			sc.Labels = map[string]string{}
+		}
+	}
+
+	if req.RestartPolicy != nil {
+		if err := req.RestartPolicy.Validate(); err != nil {
+			return sc, err
+		}
+		sc.RestartPolicy = *req.RestartPolicy
+	}
+
+	if req.StopPolicy != nil {
+		if err := req.StopPolicy.Validate(); err != nil {
+			return sc, err
+		}
+		sc.StopPolicy = *req.StopPolicy
+	}
+
+	if req.BackupPolicy != nil {
+		if err := req.BackupPolicy.Validate(); err != nil {
+			return sc, err
+		}
+		sc.BackupPolicy = *req.BackupPolicy
+	}
+
+	if req.RconPort != nil {
+		if *req.RconPort != 0 && (*req.RconPort < 1024 || *req.RconPort > 65535) {
+			return sc, errors.New("rcon_port must be 0 or between 1024 and 65535")
+		}
+		sc.RconPort = *req.RconPort
+	}
+
+	if req.QueryPort != nil {
+		if *req.QueryPort != 0 && (*req.QueryPort < 1024 || *req.QueryPort > 65535) {
+			return sc, errors.New("query_port must be 0 or between 1024 and 65535")
+		}
+		sc.QueryPort = *req.QueryPort
+	}
+
+	return sc, nil
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	policy := RestartPolicy{
+		Mode:              RestartModeAlways,
+		BackoffSeconds:    5,
+		MaxBackoffSeconds: 60,
+	}
+
+	tests := []struct {
+		name         string
+		policy       RestartPolicy
+		restartCount int
+		want         time.Duration
+	}{
+		{name: "first restart waits the base delay", policy: policy, restartCount: 0, want: 5 * time.Second},
+		{name: "second restart doubles", policy: policy, restartCount: 1, want: 10 * time.Second},
+		{name: "third restart doubles again", policy: policy, restartCount: 2, want: 20 * time.Second},
+		{name: "fourth restart doubles again", policy: policy, restartCount: 3, want: 40 * time.Second},
+		{name: "capped at the max", policy: policy, restartCount: 4, want: 60 * time.Second},
+		{name: "stays at the max", policy: policy, restartCount: 100, want: 60 * time.Second},
+		{
+			name:         "base equal to max never grows",
+			policy:       RestartPolicy{BackoffSeconds: 30, MaxBackoffSeconds: 30},
+			restartCount: 3,
+			want:         30 * time.Second,
+		},
+		{
+			name:         "cap that is not a power of two of the base",
+			policy:       RestartPolicy{BackoffSeconds: 3, MaxBackoffSeconds: 10},
+			restartCount: 2,
+			want:         10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Backoff(tt.restartCount); got != tt.want {
+				t.Errorf("Backoff(%v) = %v, want %v", tt.restartCount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartPolicyShouldRestart(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       RestartPolicy
+		exitCode     int
+		restartCount int
+		want         bool
+	}{
+		{name: "never on failure", policy: RestartPolicy{Mode: RestartModeNever}, exitCode: 1, want: false},
+		{name: "never on clean exit", policy: RestartPolicy{Mode: RestartModeNever}, exitCode: 0, want: false},
+		{name: "on failure with failure", policy: RestartPolicy{Mode: RestartModeOnFailure}, exitCode: 1, want: true},
+		{name: "on failure with clean exit", policy: RestartPolicy{Mode: RestartModeOnFailure}, exitCode: 0, want: false},
+		{name: "on failure with signal", policy: RestartPolicy{Mode: RestartModeOnFailure}, exitCode: -1, want: true},
+		{name: "always with clean exit", policy: RestartPolicy{Mode: RestartModeAlways}, exitCode: 0, want: true},
+		{
+			name:         "below max retries",
+			policy:       RestartPolicy{Mode: RestartModeAlways, MaxRetries: 3},
+			restartCount: 2,
+			want:         true,
+		},
+		{
+			name:         "max retries reached",
+			policy:       RestartPolicy{Mode: RestartModeAlways, MaxRetries: 3},
+			restartCount: 3,
+			want:         false,
+		},
+		{
+			name:         "max retries reached on failure",
+			policy:       RestartPolicy{Mode: RestartModeOnFailure, MaxRetries: 1},
+			exitCode:     1,
+			restartCount: 1,
+			want:         false,
+		},
+		{
+			name:         "no max retries is unlimited",
+			policy:       RestartPolicy{Mode: RestartModeAlways, MaxRetries: 0},
+			restartCount: 1000,
+			want:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.ShouldRestart(tt.exitCode, tt.restartCount); got != tt.want {
+				t.Errorf("ShouldRestart(%v, %v) = %v, want %v", tt.exitCode, tt.restartCount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RestartPolicy
+		wantErr bool
+	}{
+		{name: "default", policy: NewDefaultRestartPolicy(), wantErr: false},
+		{name: "unknown mode", policy: RestartPolicy{Mode: "sometimes", BackoffSeconds: 1, MaxBackoffSeconds: 1}, wantErr: true},
+		{name: "negative retries", policy: RestartPolicy{Mode: RestartModeAlways, MaxRetries: -1, BackoffSeconds: 1, MaxBackoffSeconds: 1}, wantErr: true},
+		{name: "zero backoff", policy: RestartPolicy{Mode: RestartModeAlways, BackoffSeconds: 0, MaxBackoffSeconds: 1}, wantErr: true},
+		{name: "max below base", policy: RestartPolicy{Mode: RestartModeAlways, BackoffSeconds: 10, MaxBackoffSeconds: 5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+// DEFAULT_MEMORY_RESERVE_MB is kept free for the host and the api when no reserve is configured
+const DEFAULT_MEMORY_RESERVE_MB = 1024
+
+// HostMemory accounts the memory of the host in MB, servers are admitted while their heap fits in FreeMB
+type HostMemory struct {
+	TotalMB   int `json:"total_mb"`
+	ReserveMB int `json:"reserve_mb"`
+	// CapacityMB is what servers may commit, the total minus the reserve
+	CapacityMB int `json:"capacity_mb"`
+	// CommittedMB is the sum of the maximum heap of every active server
+	CommittedMB int `json:"committed_mb"`
+	FreeMB      int `json:"free_mb"`
+	// AvailableMB is what the kernel reports as allocatable right now, whatever the servers committed
+	AvailableMB int `json:"available_mb"`
+}
+
+type GetHostResponse struct {
+	CPUCount int        `json:"cpu_count"`
+	Memory   HostMemory `json:"memory"`
+	// Commitments maps the active servers to the heap they committed in MB
+	Commitments map[string]int `json:"commitments"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// JarFlavor tells which server software a jar runs
+type JarFlavor string
+
+const (
+	JarFlavorVanilla JarFlavor = "vanilla"
+	JarFlavorPaper   JarFlavor = "paper"
+	JarFlavorSpigot  JarFlavor = "spigot"
+	JarFlavorFabric  JarFlavor = "fabric"
+	JarFlavorForge   JarFlavor = "forge"
+	JarFlavorUnknown JarFlavor = "unknown"
+)
+
+// Jar is an entry of the jar catalog, the jar files inside DIR_JAR
+type Jar struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	ModifiedAt string    `json:"modified_at"`
+	SHA256     string    `json:"sha256"`
+	Flavor     JarFlavor `json:"flavor"`
+	// Version is the minecraft version from version.json inside the jar, or from the file name when it has none
+	Version string `json:"version,omitempty"`
+	// JavaVersion is the minimum java major version the jar declares, 0 when unknown
+	JavaVersion int `json:"java_version,omitempty"`
+	// UsedBy lists the servers configured to launch the jar
+	UsedBy []string `json:"used_by"`
+}
+
+// ValidateJarName makes sure the name points to a jar file directly inside DIR_JAR
+func ValidateJarName(name string) error {
+	if name == "" {
+		return errors.New("jar cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return errors.New("jar must be a file name inside the jar folder")
+	}
+	if path.Ext(name) != ".jar" {
+		return errors.New("jar must have the .jar extension")
+	}
+
+	return nil
+}
+
+// RequiredJavaVersion is the minimum java major version of the minecraft release, 0 when the version is unknown
+func RequiredJavaVersion(version string) int {
+	switch {
+	case version == "":
+		return 0
+	case CompareVersion(version, "1.20.5") >= 0:
+		return 21
+	case CompareVersion(version, "1.18") >= 0:
+		return 17
+	case CompareVersion(version, "1.17") >= 0:
+		return 16
+	default:
+		return 8
+	}
+}
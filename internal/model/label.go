@@ -0,0 +1,213 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	MAX_SERVER_DESCRIPTION_LENGTH = 1024
+	MAX_SERVER_OWNER_LENGTH       = 128
+	MAX_SERVER_LABELS             = 64
+)
+
+var (
+	serverNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-]{0,63}$`)
+	// serverIDRegex matches the uuids servers are created with, names cannot look like one
+	serverIDRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	labelKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.\-/]{0,61}[A-Za-z0-9])?$`)
+	labelValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9_.\-]{0,61}[A-Za-z0-9])?)?$`)
+	// labelSetRegex matches the set based requirements, key in (a, b) and key notin (a, b)
+	labelSetRegex = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// ValidateServerName makes sure the name can stand for the server id in routes, an empty name is allowed
+func ValidateServerName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !serverNameRegex.MatchString(name) {
+		return errors.New("name must be letters, digits, dots, dashes or underscores up to 64 characters")
+	}
+	if serverIDRegex.MatchString(name) {
+		return errors.New("name cannot look like a server id")
+	}
+
+	return nil
+}
+
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MAX_SERVER_LABELS {
+		return fmt.Errorf("labels cannot > %v", MAX_SERVER_LABELS)
+	}
+
+	for key, value := range labels {
+		if !labelKeyRegex.MatchString(key) {
+			return fmt.Errorf("label key %q is malformed", key)
+		}
+		if !labelValueRegex.MatchString(value) {
+			return fmt.Errorf("label value %q of %v is malformed", value, key)
+		}
+	}
+
+	return nil
+}
+
+type labelOperator string
+
+const (
+	labelOperatorEqual     labelOperator = "="
+	labelOperatorNotEqual  labelOperator = "!="
+	labelOperatorIn        labelOperator = "in"
+	labelOperatorNotIn     labelOperator = "notin"
+	labelOperatorExists    labelOperator = "exists"
+	labelOperatorNotExists labelOperator = "!exists"
+)
+
+type labelRequirement struct {
+	key      string
+	operator labelOperator
+	value    string
+	// values is the set of the in and notin operators
+	values []string
+}
+
+func (lr labelRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[lr.key]
+
+	switch lr.operator {
+	case labelOperatorEqual:
+		return ok && value == lr.value
+	case labelOperatorNotEqual:
+		return !ok || value != lr.value
+	case labelOperatorIn:
+		return ok && containsLabelValue(lr.values, value)
+	case labelOperatorNotIn:
+		return !ok || !containsLabelValue(lr.values, value)
+	case labelOperatorExists:
+		return ok
+	case labelOperatorNotExists:
+		return !ok
+	}
+
+	return false
+}
+
+func containsLabelValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LabelSelector picks servers by their labels, every requirement has to match
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector reads comma separated requirements, key=value (or key==value), key!=value, key in (a, b),
+// key notin (a, b), key to require the label and !key to forbid it
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var res LabelSelector
+	if strings.TrimSpace(s) == "" {
+		return res, nil
+	}
+
+	parts, err := splitLabelSelector(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		var req labelRequirement
+		if match := labelSetRegex.FindStringSubmatch(part); match != nil {
+			req = labelRequirement{key: match[1], operator: labelOperator(match[2])}
+			for _, value := range strings.Split(match[3], ",") {
+				value = strings.TrimSpace(value)
+				if value == "" {
+					return nil, fmt.Errorf("selector %q has an empty value in its set", part)
+				}
+				if !labelValueRegex.MatchString(value) {
+					return nil, fmt.Errorf("selector %q has a malformed label value", part)
+				}
+				req.values = append(req.values, value)
+			}
+		} else {
+			switch {
+			case strings.Contains(part, "!="):
+				key, value, _ := strings.Cut(part, "!=")
+				req = labelRequirement{key: key, operator: labelOperatorNotEqual, value: value}
+			case strings.Contains(part, "=="):
+				key, value, _ := strings.Cut(part, "==")
+				req = labelRequirement{key: key, operator: labelOperatorEqual, value: value}
+			case strings.Contains(part, "="):
+				key, value, _ := strings.Cut(part, "=")
+				req = labelRequirement{key: key, operator: labelOperatorEqual, value: value}
+			case strings.HasPrefix(part, "!"):
+				req = labelRequirement{key: strings.TrimPrefix(part, "!"), operator: labelOperatorNotExists}
+			default:
+				req = labelRequirement{key: part, operator: labelOperatorExists}
+			}
+		}
+
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if !labelKeyRegex.MatchString(req.key) {
+			return nil, fmt.Errorf("selector %q has a malformed label key", part)
+		}
+		if !labelValueRegex.MatchString(req.value) {
+			return nil, fmt.Errorf("selector %q has a malformed label value", part)
+		}
+
+		res = append(res, req)
+	}
+
+	return res, nil
+}
+
+// splitLabelSelector splits the selector on the commas outside of the parentheses of the set based requirements
+func splitLabelSelector(s string) ([]string, error) {
+	var res []string
+	var depth, start int
+
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+			if depth > 1 {
+				return nil, errors.New("selector has nested parentheses")
+			}
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, errors.New("selector has an unopened parenthesis")
+			}
+		case ',':
+			if depth == 0 {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, errors.New("selector has an unclosed parenthesis")
+	}
+
+	return append(res, s[start:]), nil
+}
+
+func (ls LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range ls {
+		if !req.matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,86 @@
+package model
+
+import "testing"
+
+func TestParseLabelSelector(t *testing.T) {
+	labels := map[string]string{
+		"env":  "prod",
+		"tier": "lobby",
+		"team": "",
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		want     bool
+	}{
+		{name: "empty matches everything", selector: "", want: true},
+		{name: "blank matches everything", selector: "  ", want: true},
+		{name: "equal", selector: "env=prod", want: true},
+		{name: "double equal", selector: "env==prod", want: true},
+		{name: "equal other value", selector: "env=dev", want: false},
+		{name: "equal missing label", selector: "region=eu", want: false},
+		{name: "equal empty value", selector: "team=", want: true},
+		{name: "not equal", selector: "env!=dev", want: true},
+		{name: "not equal same value", selector: "env!=prod", want: false},
+		{name: "not equal missing label", selector: "region!=eu", want: true},
+		{name: "in", selector: "env in (dev, prod)", want: true},
+		{name: "in without spaces", selector: "env in(dev,prod)", want: true},
+		{name: "in other values", selector: "env in (dev, staging)", want: false},
+		{name: "in missing label", selector: "region in (eu)", want: false},
+		{name: "notin", selector: "env notin (dev, staging)", want: true},
+		{name: "notin listed value", selector: "env notin (dev, prod)", want: false},
+		{name: "notin missing label", selector: "region notin (eu)", want: true},
+		{name: "exists", selector: "tier", want: true},
+		{name: "exists missing label", selector: "region", want: false},
+		{name: "not exists", selector: "!region", want: true},
+		{name: "not exists present label", selector: "!tier", want: false},
+		{name: "every requirement matches", selector: "env in (dev, prod), tier=lobby, !region", want: true},
+		{name: "one requirement fails", selector: "env in (dev, prod),tier notin (lobby)", want: false},
+		{name: "prefixed key", selector: "example.com/owner!=bob", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := ParseLabelSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseLabelSelector(%q) error = %v", tt.selector, err)
+			}
+			if got := selector.Matches(labels); got != tt.want {
+				t.Errorf("ParseLabelSelector(%q).Matches() = %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLabelSelectorMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+	}{
+		{name: "missing key", selector: "=prod"},
+		{name: "missing key of not equal", selector: "!=prod"},
+		{name: "empty requirement", selector: "env=prod,,tier=lobby"},
+		{name: "trailing comma", selector: "env=prod,"},
+		{name: "malformed key", selector: "-env=prod"},
+		{name: "malformed value", selector: "env=pr od"},
+		{name: "bare not", selector: "!"},
+		{name: "in without parentheses", selector: "env in prod"},
+		{name: "in with empty set", selector: "env in ()"},
+		{name: "in with empty value", selector: "env in (dev,,prod)"},
+		{name: "in with malformed value", selector: "env in (dev, pr/od)"},
+		{name: "notin without key", selector: "notin (dev)"},
+		{name: "unknown set operator", selector: "env within (dev)"},
+		{name: "unclosed parenthesis", selector: "env in (dev, prod"},
+		{name: "unopened parenthesis", selector: "env in dev), tier=lobby"},
+		{name: "nested parentheses", selector: "env in ((dev))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseLabelSelector(tt.selector); err == nil {
+				t.Errorf("ParseLabelSelector(%q) error = nil, want an error", tt.selector)
+			}
+		})
+	}
+}
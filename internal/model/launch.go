@@ -0,0 +1,279 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const DEFAULT_LAUNCH_PROFILE = "aikar"
+
+// LAUNCH_PROFILES are the named jvm flag sets a server can launch with
+var LAUNCH_PROFILES = map[string][]string{
+	"aikar": DEFAULT_JVM_FLAGS,
+	"zgc": {
+		"-XX:+UseZGC",
+		"-XX:+AlwaysPreTouch",
+		"-XX:+DisableExplicitGC",
+		"-XX:+PerfDisableSharedMem",
+	},
+	"minimal": {},
+}
+
+var (
+	// jvmBoolFlagRegex matches the boolean tuning flags, -XX:+Name and -XX:-Name
+	jvmBoolFlagRegex = regexp.MustCompile(`^-XX:[+-][A-Za-z][A-Za-z0-9_]*$`)
+	// jvmValueFlagRegex matches -XX:Name=value, the value cannot hold a path
+	jvmValueFlagRegex = regexp.MustCompile(`^-XX:([A-Za-z][A-Za-z0-9_]*)=([A-Za-z0-9_.,+\-]+)$`)
+	// jvmSizeFlagRegex matches the thread stack and young generation sizes
+	jvmSizeFlagRegex = regexp.MustCompile(`^-X(ss|mn)[0-9]+[kKmMgG]?$`)
+)
+
+// deniedJVMOptions are the -XX options naming files to read or write or commands to run, even a plain file name is
+// resolved against a folder the api does not manage
+var deniedJVMOptions = map[string]bool{
+	"AOTCache":                 true,
+	"AOTCacheOutput":           true,
+	"AOTConfiguration":         true,
+	"AOTLibrary":               true,
+	"ArchiveClassesAtExit":     true,
+	"CompileCommandFile":       true,
+	"CRaCCheckpointTo":         true,
+	"CRaCRestoreFrom":          true,
+	"ErrorFile":                true,
+	"ExtraSharedClassListFile": true,
+	"Flags":                    true,
+	"FlightRecorderOptions":    true,
+	"HeapDumpPath":             true,
+	"JVMCILibPath":             true,
+	"LogFile":                  true,
+	"OnError":                  true,
+	"OnOutOfMemoryError":       true,
+	"PerfDataSaveFile":         true,
+	"SharedArchiveFile":        true,
+	"SharedClassListFile":      true,
+	"StartFlightRecording":     true,
+	"VMOptionsFile":            true,
+}
+
+// managedServerArgs are set by the api from the server config
+var managedServerArgs = []string{"port", "server-port", "world", "level-name", "nogui"}
+
+// deniedServerArgs point the server at files and folders outside the server folder, or at foreign code, moving the
+// worlds away breaks backups, restores, uploads and clones
+var deniedServerArgs = []string{
+	"universe",
+	"world-container",
+	"world-dir",
+	"config",
+	"plugins",
+	"add-plugin",
+	"add-extra-plugin-jar",
+	"bukkit-settings",
+	"spigot-settings",
+	"commands-settings",
+	"paper-settings",
+	"paper-settings-directory",
+	"paper-dir",
+	"pidFile",
+}
+
+var systemPropertyKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.\-]*$`)
+
+// LaunchConfig shapes the java command line of a server
+type LaunchConfig struct {
+	// Profile names the base jvm flags, see LAUNCH_PROFILES
+	Profile       string   `json:"profile"`
+	ExtraJVMFlags []string `json:"extra_jvm_flags"`
+	// SystemProperties are passed as -Dkey=value
+	SystemProperties map[string]string `json:"system_properties"`
+	// XmsMB and XmxMB override the heap derived from ram_gb, 0 keeps it
+	XmsMB int `json:"xms_mb,omitempty"`
+	XmxMB int `json:"xmx_mb,omitempty"`
+	// JavaPath is an absolute path to the java binary, java is taken from PATH when empty
+	JavaPath        string   `json:"java_path,omitempty"`
+	ExtraServerArgs []string `json:"extra_server_args"`
+}
+
+func NewDefaultLaunchConfig() LaunchConfig {
+	return LaunchConfig{
+		Profile:          DEFAULT_LAUNCH_PROFILE,
+		ExtraJVMFlags:    []string{},
+		SystemProperties: map[string]string{},
+		ExtraServerArgs:  []string{},
+	}
+}
+
+func (lc LaunchConfig) Validate() error {
+	if _, ok := LAUNCH_PROFILES[lc.Profile]; !ok {
+		return fmt.Errorf("unknown launch profile %v", lc.Profile)
+	}
+
+	for _, flag := range lc.ExtraJVMFlags {
+		if err := ValidateJVMFlag(flag); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range lc.SystemProperties {
+		if !systemPropertyKeyRegex.MatchString(key) {
+			return fmt.Errorf("system property %q is malformed", key)
+		}
+		if hasControlChar(value) {
+			return fmt.Errorf("system property %v cannot contain control characters", key)
+		}
+	}
+
+	if lc.XmsMB < 0 || lc.XmxMB < 0 {
+		return errors.New("xms_mb and xmx_mb cannot < 0")
+	}
+	if lc.XmxMB != 0 && lc.XmxMB < 256 {
+		return errors.New("xmx_mb cannot < 256")
+	}
+	if lc.XmsMB != 0 && lc.XmxMB != 0 && lc.XmsMB > lc.XmxMB {
+		return errors.New("xms_mb cannot > xmx_mb")
+	}
+
+	if lc.JavaPath != "" {
+		if !filepath.IsAbs(lc.JavaPath) || filepath.Clean(lc.JavaPath) != lc.JavaPath {
+			return errors.New("java_path must be a clean absolute path")
+		}
+		if hasControlChar(lc.JavaPath) {
+			return errors.New("java_path cannot contain control characters")
+		}
+	}
+
+	for _, arg := range lc.ExtraServerArgs {
+		if err := ValidateServerArg(arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateJVMFlag only lets tuning flags through, -XX:+Name, -XX:-Name, -XX:Name=value without a path, -Xss and
+// -Xmn, heap and system properties have their own settings
+func ValidateJVMFlag(flag string) error {
+	if !strings.HasPrefix(flag, "-") || len(flag) < 2 {
+		return fmt.Errorf("jvm flag %q must start with -", flag)
+	}
+	if len(flag) > 512 || hasControlChar(flag) || strings.ContainsAny(flag, " \t") {
+		return fmt.Errorf("jvm flag %q is malformed", flag)
+	}
+
+	if jvmBoolFlagRegex.MatchString(flag) || jvmSizeFlagRegex.MatchString(flag) {
+		return nil
+	}
+	if match := jvmValueFlagRegex.FindStringSubmatch(flag); match != nil && !deniedJVMOptions[match[1]] {
+		return nil
+	}
+
+	return fmt.Errorf("jvm flag %v is not allowed", flag)
+}
+
+// ValidateServerArg rejects the server arguments managed by the api or pointing outside the server folder, the server
+// takes unambiguous abbreviations of long options so a prefix of a denied option is denied as well
+func ValidateServerArg(arg string) error {
+	if arg == "" || hasControlChar(arg) {
+		return fmt.Errorf("server argument %q is malformed", arg)
+	}
+
+	// vanilla takes nogui without dashes
+	if arg == "nogui" {
+		return fmt.Errorf("server argument %v is managed by the api", arg)
+	}
+
+	if !strings.HasPrefix(arg, "-") {
+		return nil
+	}
+	if !strings.HasPrefix(arg, "--") {
+		return fmt.Errorf("server argument %v must use the long -- form", arg)
+	}
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+	if name == "" {
+		return fmt.Errorf("server argument %q is malformed", arg)
+	}
+	for _, managed := range managedServerArgs {
+		if strings.HasPrefix(managed, name) {
+			return fmt.Errorf("server argument %v is managed by the api", arg)
+		}
+	}
+	for _, denied := range deniedServerArgs {
+		if strings.HasPrefix(denied, name) {
+			return fmt.Errorf("server argument %v is not allowed", arg)
+		}
+	}
+
+	return nil
+}
+
+func hasControlChar(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) >= 0
+}
+
+// GetHeapMB returns the initial and maximum heap of the server in MB
+func (sc ServerConfig) GetHeapMB() (int, int) {
+	xmx := sc.Launch.XmxMB
+	if xmx == 0 {
+		xmx = sc.RamGB * 1024
+	}
+
+	xms := sc.Launch.XmsMB
+	if xms == 0 || xms > xmx {
+		xms = xmx
+	}
+
+	return xms, xmx
+}
+
+// GetJVMFlags returns the profile flags, or JVMFlags when set, followed by the extra flags and system properties
+func (sc ServerConfig) GetJVMFlags() []string {
+	res := append([]string{}, LAUNCH_PROFILES[sc.Launch.Profile]...)
+	if sc.JVMFlags != nil {
+		res = append([]string{}, sc.JVMFlags...)
+	}
+	res = append(res, sc.Launch.ExtraJVMFlags...)
+
+	keys := make([]string, 0, len(sc.Launch.SystemProperties))
+	for key := range sc.Launch.SystemProperties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		res = append(res, fmt.Sprintf("-D%v=%v", key, sc.Launch.SystemProperties[key]))
+	}
+
+	return res
+}
+
+// GetLaunchArgs returns the java arguments of the server, the jar is resolved from the server folder
+func (sc ServerConfig) GetLaunchArgs() []string {
+	xms, xmx := sc.GetHeapMB()
+
+	res := sc.GetJVMFlags()
+	res = append(res,
+		// set ram useage
+		fmt.Sprintf("-Xms%vM", xms),
+		fmt.Sprintf("-Xmx%vM", xmx),
+
+		// set jar file
+		"-jar", filepath.Join("..", "..", "jar", sc.Jar),
+
+		// set port
+		"--port", fmt.Sprint(sc.Port),
+
+		"--nogui",
+	)
+
+	if sc.WorldName != "" {
+		res = append(res, "--world", sc.WorldName)
+	}
+
+	return append(res, sc.Launch.ExtraServerArgs...)
+}
@@ -0,0 +1,93 @@
+package model
+
+import "testing"
+
+func TestValidateJVMFlag(t *testing.T) {
+	tests := []struct {
+		flag    string
+		wantErr bool
+	}{
+		{flag: "-XX:+UseG1GC", wantErr: false},
+		{flag: "-XX:-UseBiasedLocking", wantErr: false},
+		{flag: "-XX:MaxGCPauseMillis=200", wantErr: false},
+		{flag: "-XX:G1HeapRegionSize=8M", wantErr: false},
+		{flag: "-XX:NativeMemoryTracking=summary", wantErr: false},
+		{flag: "-XX:+HeapDumpOnOutOfMemoryError", wantErr: false},
+		{flag: "-Xss2M", wantErr: false},
+		{flag: "-Xmn512m", wantErr: false},
+		{flag: "-Xss1024", wantErr: false},
+		{flag: "", wantErr: true},
+		{flag: "-", wantErr: true},
+		{flag: "XX:+UseG1GC", wantErr: true},
+		{flag: "-XX:+Use G1GC", wantErr: true},
+		{flag: "-Xmx4G", wantErr: true},
+		{flag: "-Xms4G", wantErr: true},
+		{flag: "-Dfoo=bar", wantErr: true},
+		{flag: "-jar", wantErr: true},
+		{flag: "-cp", wantErr: true},
+		{flag: "-javaagent:/tmp/agent.jar", wantErr: true},
+		{flag: "-agentpath:/tmp/agent.so", wantErr: true},
+		{flag: "-Xbootclasspath/a:/tmp", wantErr: true},
+		{flag: "-Xlog:gc:file=/tmp/gc.log", wantErr: true},
+		{flag: "-Xlog:gc", wantErr: true},
+		{flag: "-Xss2M/../x", wantErr: true},
+		{flag: "-XX:ErrorFile=/tmp/err.log", wantErr: true},
+		{flag: "-XX:ErrorFile=err.log", wantErr: true},
+		{flag: "-XX:HeapDumpPath=/tmp", wantErr: true},
+		{flag: "-XX:HeapDumpPath=dumps", wantErr: true},
+		{flag: "-XX:LogFile=/tmp/vm.log", wantErr: true},
+		{flag: "-XX:OnOutOfMemoryError=kill", wantErr: true},
+		{flag: "-XX:VMOptionsFile=opts", wantErr: true},
+		{flag: "-XX:StartFlightRecording=filename=/tmp/x.jfr", wantErr: true},
+		{flag: "-XX:CompileCommand=exclude,java/lang/Object.wait", wantErr: true},
+		{flag: "-XX:SomeOption=C:\\dump", wantErr: true},
+		{flag: "--add-opens=java.base/java.lang=ALL-UNNAMED", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			if err := ValidateJVMFlag(tt.flag); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateJVMFlag(%q) error = %v, wantErr %v", tt.flag, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServerArg(t *testing.T) {
+	tests := []struct {
+		arg     string
+		wantErr bool
+	}{
+		{arg: "--forceUpgrade", wantErr: false},
+		{arg: "--eraseCache", wantErr: false},
+		{arg: "--safeMode", wantErr: false},
+		{arg: "--max-players=50", wantErr: false},
+		{arg: "50", wantErr: false},
+		{arg: "", wantErr: true},
+		{arg: "--", wantErr: true},
+		{arg: "nogui", wantErr: true},
+		{arg: "--nogui", wantErr: true},
+		{arg: "--port", wantErr: true},
+		{arg: "--port=25566", wantErr: true},
+		{arg: "--world=other", wantErr: true},
+		{arg: "--universe", wantErr: true},
+		{arg: "--universe=/tmp/worlds", wantErr: true},
+		{arg: "--univ=/tmp/worlds", wantErr: true},
+		{arg: "--world-container=/tmp/worlds", wantErr: true},
+		{arg: "--world-dir=/tmp/worlds", wantErr: true},
+		{arg: "--plugins=/tmp/plugins", wantErr: true},
+		{arg: "--add-plugin=/tmp/evil.jar", wantErr: true},
+		{arg: "--config=/tmp/server.properties", wantErr: true},
+		{arg: "--pidFile=/tmp/pid", wantErr: true},
+		{arg: "-W/tmp/worlds", wantErr: true},
+		{arg: "-p", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if err := ValidateServerArg(tt.arg); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServerArg(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+)
+
+var logLineRegex = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\] \[([^\]]*)/([A-Z]+)\]: ?(.*)$`)
+
+type LogEntry struct {
+	// Line is the 1-based line of msa.std the entry starts on, it doubles as the pagination cursor
+	Line    int    `json:"line"`
+	Time    string `json:"time,omitempty"`
+	Thread  string `json:"thread,omitempty"`
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message"`
+}
+
+// ParseLogLines turns the console output into entries, stack traces are folded into the entry they belong to
+// and any other line that is not in the `[HH:MM:SS] [thread/LEVEL]: message` format becomes a bare entry
+func ParseLogLines(data string) []LogEntry {
+	var res []LogEntry
+	for i, line := range strings.Split(strings.TrimRight(data, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if match := logLineRegex.FindStringSubmatch(line); match != nil {
+			res = append(res, LogEntry{
+				Line:    i + 1,
+				Time:    match[1],
+				Thread:  match[2],
+				Level:   match[3],
+				Message: match[4],
+			})
+			continue
+		}
+
+		if len(res) > 0 && isLogContinuation(line) {
+			res[len(res)-1].Message += "\n" + line
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		res = append(res, LogEntry{
+			Line:    i + 1,
+			Message: line,
+		})
+	}
+
+	return res
+}
+
+func isLogContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "Caused by:")
+}
+
+type LogFilter struct {
+	// Levels and Thread are matched case-insensitively, Thread as a substring
+	Levels []string
+	Thread string
+	// From and To bound the entry time as HH:MM:SS, both inclusive
+	From  string
+	To    string
+	Regex *regexp.Regexp
+
+	// Cursor skips every entry up to and including that line
+	Cursor int
+	Limit  int
+}
+
+func (lf LogFilter) Match(entry LogEntry) bool {
+	if entry.Line <= lf.Cursor {
+		return false
+	}
+
+	if len(lf.Levels) > 0 {
+		isMatch := false
+		for _, level := range lf.Levels {
+			if strings.EqualFold(level, entry.Level) {
+				isMatch = true
+				break
+			}
+		}
+		if !isMatch {
+			return false
+		}
+	}
+
+	if lf.Thread != "" && !strings.Contains(strings.ToLower(entry.Thread), strings.ToLower(lf.Thread)) {
+		return false
+	}
+
+	if lf.From != "" && (entry.Time == "" || entry.Time < lf.From) {
+		return false
+	}
+
+	if lf.To != "" && (entry.Time == "" || entry.Time > lf.To) {
+		return false
+	}
+
+	if lf.Regex != nil && !lf.Regex.MatchString(entry.Message) {
+		return false
+	}
+
+	return true
+}
+
+type GetServerLogsResponse struct {
+	Entries []LogEntry `json:"entries"`
+	// NextCursor is the line of the last returned entry, passing it back resumes the listing or follows new output
+	NextCursor int  `json:"next_cursor"`
+	HasMore    bool `json:"has_more"`
+}
+
+type ExecuteCommandResponse struct {
+	Command string   `json:"command"`
+	Output  []string `json:"output"`
+	// IsMatched tells whether the output ended on the until matcher rather than on the wait window
+	IsMatched bool `json:"is_matched"`
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestParseLogLines(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []LogEntry
+	}{
+		{
+			name: "regular lines",
+			data: "[10:00:00] [Server thread/INFO]: Starting minecraft server version 1.19.2\n" +
+				"[10:00:01] [Server thread/WARN]: **** SERVER IS RUNNING IN OFFLINE/INSECURE MODE!\n",
+			want: []LogEntry{
+				{Line: 1, Time: "10:00:00", Thread: "Server thread", Level: "INFO", Message: "Starting minecraft server version 1.19.2"},
+				{Line: 2, Time: "10:00:01", Thread: "Server thread", Level: "WARN", Message: "**** SERVER IS RUNNING IN OFFLINE/INSECURE MODE!"},
+			},
+		},
+		{
+			name: "stack trace is folded into its entry",
+			data: "[10:00:00] [Server thread/ERROR]: Encountered an unexpected exception\n" +
+				"java.lang.IllegalStateException: boom\n" +
+				"\tat net.minecraft.server.Main.main(Main.java:1)\n" +
+				"Caused by: java.lang.NullPointerException\n" +
+				"    ... 3 more\n" +
+				"[10:00:02] [Server thread/INFO]: Stopping server\n",
+			want: []LogEntry{
+				{Line: 1, Time: "10:00:00", Thread: "Server thread", Level: "ERROR", Message: "Encountered an unexpected exception"},
+				{Line: 2, Message: "java.lang.IllegalStateException: boom\n" +
+					"\tat net.minecraft.server.Main.main(Main.java:1)\n" +
+					"Caused by: java.lang.NullPointerException\n" +
+					"    ... 3 more"},
+				{Line: 6, Time: "10:00:02", Thread: "Server thread", Level: "INFO", Message: "Stopping server"},
+			},
+		},
+		{
+			name: "continuation before any entry is a bare entry",
+			data: "\tat somewhere\n",
+			want: []LogEntry{
+				{Line: 1, Message: "\tat somewhere"},
+			},
+		},
+		{
+			name: "unknown level is kept as is",
+			data: "[10:00:00] [Worker-Main-1/FATAL]: out of memory\n",
+			want: []LogEntry{
+				{Line: 1, Time: "10:00:00", Thread: "Worker-Main-1", Level: "FATAL", Message: "out of memory"},
+			},
+		},
+		{
+			name: "unformatted lines, blank lines and crlf",
+			data: "Starting net.minecraft.server.Main\r\n\r\n[10:00:00] [main/INFO]: Loaded 7 recipes\r\n",
+			want: []LogEntry{
+				{Line: 1, Message: "Starting net.minecraft.server.Main"},
+				{Line: 3, Time: "10:00:00", Thread: "main", Level: "INFO", Message: "Loaded 7 recipes"},
+			},
+		},
+		{
+			name: "empty output",
+			data: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseLogLines(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLogLines() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogFilterMatch(t *testing.T) {
+	info := LogEntry{Line: 5, Time: "10:00:00", Thread: "Server thread", Level: "INFO", Message: "Done (1.0s)!"}
+	bare := LogEntry{Line: 6, Message: "Starting net.minecraft.server.Main"}
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		entry  LogEntry
+		want   bool
+	}{
+		{name: "empty filter", filter: LogFilter{}, entry: info, want: true},
+		{name: "level matches case insensitively", filter: LogFilter{Levels: []string{"warn", "info"}}, entry: info, want: true},
+		{name: "level does not match", filter: LogFilter{Levels: []string{"ERROR"}}, entry: info, want: false},
+		{name: "unknown level filters everything out", filter: LogFilter{Levels: []string{"VERBOSE"}}, entry: info, want: false},
+		{name: "bare entry has no level", filter: LogFilter{Levels: []string{"INFO"}}, entry: bare, want: false},
+		{name: "thread substring", filter: LogFilter{Thread: "server"}, entry: info, want: true},
+		{name: "thread mismatch", filter: LogFilter{Thread: "worker"}, entry: info, want: false},
+		{name: "since is inclusive", filter: LogFilter{From: "10:00:00"}, entry: info, want: true},
+		{name: "before since", filter: LogFilter{From: "10:00:01"}, entry: info, want: false},
+		{name: "until is inclusive", filter: LogFilter{To: "10:00:00"}, entry: info, want: true},
+		{name: "after until", filter: LogFilter{To: "09:59:59"}, entry: info, want: false},
+		{name: "inside since and until", filter: LogFilter{From: "09:00:00", To: "11:00:00"}, entry: info, want: true},
+		{name: "bare entry has no time", filter: LogFilter{From: "00:00:00"}, entry: bare, want: false},
+		{name: "bare entry without time bound", filter: LogFilter{}, entry: bare, want: true},
+		{name: "regex", filter: LogFilter{Regex: regexp.MustCompile(`^Done`)}, entry: info, want: true},
+		{name: "regex mismatch", filter: LogFilter{Regex: regexp.MustCompile(`^Stopping`)}, entry: info, want: false},
+		{name: "cursor skips up to the line", filter: LogFilter{Cursor: 5}, entry: info, want: false},
+		{name: "cursor before the line", filter: LogFilter{Cursor: 4}, entry: info, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.entry); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ProcessMetrics is a sample of the process of a running server
+type ProcessMetrics struct {
+	SampledAt time.Time `json:"sampled_at"`
+	// CPUPercent is the cpu used since the previous sample, 100 being one full core
+	CPUPercent    float64 `json:"cpu_percent"`
+	RSSBytes      uint64  `json:"rss_bytes"`
+	Threads       int     `json:"threads"`
+	OpenFDs       int     `json:"open_fds"`
+	UptimeSeconds int64   `json:"uptime_seconds"`
+}
+
+type GetServerMetricsResponse struct {
+	// Current is the latest sample, nil when the server is not running
+	Current *ProcessMetrics `json:"current"`
+	// History holds the latest samples of the current or last process, oldest first
+	History         []ProcessMetrics `json:"history"`
+	IntervalSeconds int              `json:"interval_seconds"`
+}
@@ -0,0 +1,224 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type PropertyType string
+
+const (
+	PropertyTypeBool   PropertyType = "bool"
+	PropertyTypeInt    PropertyType = "int"
+	PropertyTypeString PropertyType = "string"
+	PropertyTypeEnum   PropertyType = "enum"
+)
+
+type PropertySchema struct {
+	Type PropertyType
+	Min  int
+	Max  int
+	// Values lists the allowed values of an enum
+	Values []string
+	// Since and Until bound the versions knowing the property, empty means unbounded
+	Since string
+	Until string
+	// IsManaged properties are written by the api on every start and cannot be changed by hand
+	IsManaged bool
+}
+
+func boolProperty() PropertySchema {
+	return PropertySchema{Type: PropertyTypeBool}
+}
+
+func intProperty(min, max int) PropertySchema {
+	return PropertySchema{Type: PropertyTypeInt, Min: min, Max: max}
+}
+
+func stringProperty() PropertySchema {
+	return PropertySchema{Type: PropertyTypeString}
+}
+
+func enumProperty(values ...string) PropertySchema {
+	return PropertySchema{Type: PropertyTypeEnum, Values: values}
+}
+
+func (ps PropertySchema) since(version string) PropertySchema {
+	ps.Since = version
+	return ps
+}
+
+func (ps PropertySchema) until(version string) PropertySchema {
+	ps.Until = version
+	return ps
+}
+
+func (ps PropertySchema) managed() PropertySchema {
+	ps.IsManaged = true
+	return ps
+}
+
+// SERVER_PROPERTIES_SCHEMA describes the vanilla server.properties keys
+var SERVER_PROPERTIES_SCHEMA = map[string]PropertySchema{
+	"accepts-transfers":                 boolProperty().since("1.20.5"),
+	"allow-flight":                      boolProperty(),
+	"allow-nether":                      boolProperty(),
+	"broadcast-console-to-ops":          boolProperty(),
+	"broadcast-rcon-to-ops":             boolProperty(),
+	"bug-report-link":                   stringProperty().since("1.21"),
+	"debug":                             boolProperty(),
+	"difficulty":                        enumProperty("peaceful", "easy", "normal", "hard"),
+	"enable-command-block":              boolProperty(),
+	"enable-jmx-monitoring":             boolProperty(),
+	"enable-query":                      boolProperty().managed(),
+	"enable-rcon":                       boolProperty().managed(),
+	"enable-status":                     boolProperty(),
+	"enforce-secure-profile":            boolProperty().since("1.19"),
+	"enforce-whitelist":                 boolProperty(),
+	"entity-broadcast-range-percentage": intProperty(10, 1000),
+	"force-gamemode":                    boolProperty(),
+	"function-permission-level":         intProperty(1, 4),
+	"gamemode":                          enumProperty("survival", "creative", "adventure", "spectator"),
+	"generate-structures":               boolProperty(),
+	"generator-settings":                stringProperty(),
+	"hardcore":                          boolProperty(),
+	"hide-online-players":               boolProperty().since("1.18"),
+	"initial-disabled-packs":            stringProperty().since("1.19.3"),
+	"initial-enabled-packs":             stringProperty().since("1.19.3"),
+	"level-name":                        stringProperty(),
+	"level-seed":                        stringProperty(),
+	"level-type":                        stringProperty(),
+	"log-ips":                           boolProperty().since("1.20.2"),
+	"max-chained-neighbor-updates":      intProperty(-1, math.MaxInt32).since("1.19"),
+	"max-players":                       intProperty(0, math.MaxInt32),
+	"max-tick-time":                     intProperty(-1, math.MaxInt32),
+	"max-world-size":                    intProperty(1, 29999984),
+	"motd":                              stringProperty(),
+	"network-compression-threshold":     intProperty(-1, 65535),
+	"online-mode":                       boolProperty(),
+	"op-permission-level":               intProperty(0, 4),
+	"pause-when-empty-seconds":          intProperty(0, math.MaxInt32).since("1.21.2"),
+	"player-idle-timeout":               intProperty(0, math.MaxInt32),
+	"prevent-proxy-connections":         boolProperty(),
+	"previews-chat":                     boolProperty().since("1.19").until("1.19.2"),
+	"pvp":                               boolProperty(),
+	"query.port":                        intProperty(1, 65535).managed(),
+	"rate-limit":                        intProperty(0, math.MaxInt32),
+	"rcon.password":                     stringProperty().managed(),
+	"rcon.port":                         intProperty(1, 65535).managed(),
+	"region-file-compression":           enumProperty("deflate", "lz4", "none").since("1.20.5"),
+	"require-resource-pack":             boolProperty().since("1.17"),
+	"resource-pack":                     stringProperty(),
+	"resource-pack-id":                  stringProperty().since("1.20.3"),
+	"resource-pack-prompt":              stringProperty().since("1.17"),
+	"resource-pack-sha1":                stringProperty(),
+	"server-ip":                         stringProperty(),
+	"server-port":                       intProperty(1, 65535).managed(),
+	"simulation-distance":               intProperty(3, 32).since("1.18"),
+	"snooper-enabled":                   boolProperty().until("1.17.1"),
+	"spawn-animals":                     boolProperty(),
+	"spawn-monsters":                    boolProperty(),
+	"spawn-npcs":                        boolProperty(),
+	"spawn-protection":                  intProperty(0, math.MaxInt32),
+	"sync-chunk-writes":                 boolProperty(),
+	"text-filtering-config":             stringProperty(),
+	"use-native-transport":              boolProperty(),
+	"view-distance":                     intProperty(3, 32),
+	"white-list":                        boolProperty(),
+}
+
+// ValidateProperty checks the key is known to the version and the value has the expected type, an empty version
+// skips the version check
+func ValidateProperty(version, key, value string) error {
+	schema, ok := SERVER_PROPERTIES_SCHEMA[key]
+	if !ok {
+		return fmt.Errorf("unknown property %v", key)
+	}
+
+	if schema.IsManaged {
+		return fmt.Errorf("property %v is managed by the api", key)
+	}
+
+	if version != "" {
+		if schema.Since != "" && CompareVersion(version, schema.Since) < 0 {
+			return fmt.Errorf("property %v requires version %v or later", key, schema.Since)
+		}
+		if schema.Until != "" && CompareVersion(version, schema.Until) > 0 {
+			return fmt.Errorf("property %v was removed after version %v", key, schema.Until)
+		}
+	}
+
+	// the world is a folder inside the server folder, backups and uploads rely on it
+	if key == "level-name" {
+		if err := ValidateWorldName(value); err != nil {
+			return fmt.Errorf("property %v: %v", key, err)
+		}
+	}
+
+	switch schema.Type {
+	case PropertyTypeBool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("property %v must be true or false", key)
+		}
+	case PropertyTypeInt:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("property %v must be an integer", key)
+		}
+		if v < schema.Min || v > schema.Max {
+			return fmt.Errorf("property %v must be between %v and %v", key, schema.Min, schema.Max)
+		}
+	case PropertyTypeEnum:
+		for _, v := range schema.Values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("property %v must be one of %v", key, strings.Join(schema.Values, ", "))
+	}
+
+	return nil
+}
+
+var versionRegex = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// ParseVersion finds a release version such as 1.19.2 in the text, e.g. a jar file name
+func ParseVersion(s string) string {
+	return versionRegex.FindString(s)
+}
+
+// CompareVersion compares dotted release versions, missing parts count as 0
+func CompareVersion(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var va, vb int
+		if i < len(partsA) {
+			va, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			vb, _ = strconv.Atoi(partsB[i])
+		}
+
+		if va != vb {
+			if va < vb {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// UpdateServerPropertiesRequest maps property keys to their new value, a nil value removes the property
+type UpdateServerPropertiesRequest map[string]*string
+
+type ServerPropertiesResponse struct {
+	Properties map[string]string `json:"properties"`
+	// IsRestartRequired is set when properties changed while the server was running
+	IsRestartRequired bool `json:"is_restart_required"`
+}
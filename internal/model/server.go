@@ -1,39 +1,236 @@
 package model
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path"
 	"regexp"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/rcon"
+	"github.com/Bearaujus/minecraft-server-api/pkg/slp"
 )
 
 var (
-	DIR_JAR    = path.Join("file", "jar")
-	DIR_SERVER = path.Join("file", "server")
+	DIR_JAR      = path.Join("file", "jar")
+	DIR_SERVER   = path.Join("file", "server")
+	DIR_BACKUP   = path.Join("file", "backup")
+	DIR_TEMPLATE = path.Join("file", "template")
+)
+
+const (
+	FILE_SERVER_PID = "msa.pid"
+)
+
+// OrphanPolicy decides what happens on boot to a server process left running by a previous instance of the api
+type OrphanPolicy string
+
+const (
+	OrphanPolicyAdopt OrphanPolicy = "adopt"
+	OrphanPolicyKill  OrphanPolicy = "kill"
+)
+
+func (op OrphanPolicy) Validate() error {
+	switch op {
+	case OrphanPolicyAdopt, OrphanPolicyKill:
+		return nil
+	}
+
+	return fmt.Errorf("unknown orphan policy %v", op)
+}
+
+type ServerStatus string
+
+const (
+	ServerStatusCreated  ServerStatus = "created"
+	ServerStatusStopped  ServerStatus = "stopped"
+	ServerStatusStarting ServerStatus = "starting"
+	ServerStatusRunning  ServerStatus = "running"
+	ServerStatusStopping ServerStatus = "stopping"
+	ServerStatusCrashed  ServerStatus = "crashed"
 )
 
+// serverStatusTransitions lists every status a server is allowed to move to from its current status
+var serverStatusTransitions = map[ServerStatus][]ServerStatus{
+	ServerStatusCreated:  {ServerStatusStarting},
+	ServerStatusStopped:  {ServerStatusStarting},
+	ServerStatusStarting: {ServerStatusRunning, ServerStatusStopping, ServerStatusStopped, ServerStatusCrashed},
+	ServerStatusRunning:  {ServerStatusStopping, ServerStatusStopped, ServerStatusCrashed},
+	ServerStatusStopping: {ServerStatusStopped, ServerStatusCrashed},
+	ServerStatusCrashed:  {ServerStatusStarting},
+}
+
+func (ss ServerStatus) CanTransitionTo(next ServerStatus) bool {
+	for _, v := range serverStatusTransitions[ss] {
+		if v == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsActive reports whether a process is attached to the server
+func (ss ServerStatus) IsActive() bool {
+	return ss == ServerStatusStarting || ss == ServerStatusRunning || ss == ServerStatusStopping
+}
+
+// StopOutcome tells which step of the stop sequence brought the process down
+type StopOutcome string
+
+const (
+	StopOutcomeStopped    StopOutcome = "stopped"
+	StopOutcomeTerminated StopOutcome = "terminated"
+	StopOutcomeKilled     StopOutcome = "killed"
+)
+
+// StopServerRequest overrides the stop policy of the server for a single stop, nil fields keep the policy
+type StopServerRequest struct {
+	GraceSeconds *int
+	TermSeconds  *int
+	WarnSeconds  *int
+	// Wait blocks the call until the process has exited
+	Wait bool
+}
+
+func (req StopServerRequest) Apply(sp StopPolicy) (StopPolicy, error) {
+	if req.GraceSeconds != nil {
+		sp.GraceSeconds = *req.GraceSeconds
+	}
+	if req.TermSeconds != nil {
+		sp.TermSeconds = *req.TermSeconds
+	}
+	if req.WarnSeconds != nil {
+		sp.WarnSeconds = *req.WarnSeconds
+	}
+
+	return sp, sp.Validate()
+}
+
 type Server struct {
-	ID    string
-	Port  int
-	RamGB int
+	ID     string
+	Port   int
+	RamGB  int
+	Status ServerStatus
+	Config ServerConfig
 
 	Cmd       *exec.Cmd
 	StdinPipe *io.WriteCloser
 	FileOut   *os.File
 
-	IsAttemptedToStart bool
-	IsAttemptedToStop  bool
+	// Process is set for both spawned and re-adopted servers, IsAdopted servers have no Cmd nor StdinPipe
+	Pid       int
+	Process   *os.Process
+	IsAdopted bool
+	// LaunchedXmxMB is the maximum heap the process runs with, config changes only apply on the next launch
+	LaunchedXmxMB int
+
+	// RconAddress is set once rcon was configured for the running process, Rcon is connected on first use
+	RconAddress  string
+	RconPassword string
+	Rcon         *rcon.Client
+	// QueryAddress is the udp address of the query listener of the running process
+	QueryAddress string
+
+	// Console mirrors the output of the server, it lives as long as the server is registered
+	Console *pkg.Broadcaster
+
+	// IsRestarting is set while a restart request walks the server through stopping and starting
+	IsRestarting bool
+	// IsBackingUp is set while a backup archives the worlds or a clone copies them, the server cannot start or be
+	// deleted meanwhile, LastBackupAt is the time of the latest backup taken
+	IsBackingUp  bool
+	LastBackupAt time.Time
+	// IsRestoring is set while a restore swaps the worlds, the server cannot start or be deleted meanwhile
+	IsRestoring bool
+
+	// IsRestartRequired is set when server.properties changed while the process was attached, the next launch clears it
+	IsRestartRequired bool
+
+	// Exited is closed by the supervisor once the process has exited and the status was updated
+	Exited chan struct{}
+
+	// filled by the supervisor once the process exits, RestartCount is reset on every manual start
+	RestartCount   int
+	LastExitCode   *int
+	LastExitReason string
+	LastExitAt     time.Time
+	NextRestartAt  time.Time
+
+	LastStopOutcome StopOutcome
+}
+
+func (s *Server) RecordExit(exitCode int, reason string) {
+	s.LastExitCode = &exitCode
+	s.LastExitReason = reason
+	s.LastExitAt = time.Now()
+}
+
+// SetStatus moves the server to the next status, detaching the process once the server is no longer active
+func (s *Server) SetStatus(next ServerStatus) error {
+	if !s.Status.CanTransitionTo(next) {
+		return fmt.Errorf("server cannot change status from %v to %v", s.Status, next)
+	}
+	s.Status = next
+
+	if !next.IsActive() {
+		if s.FileOut != nil {
+			s.FileOut.Close()
+		}
+		s.Cmd = nil
+		s.StdinPipe = nil
+		s.FileOut = nil
+		s.Pid = 0
+		s.Process = nil
+		s.LaunchedXmxMB = 0
+		s.IsAdopted = false
+		s.Exited = nil
+		if s.Rcon != nil {
+			s.Rcon.Close()
+		}
+		s.RconAddress = ""
+		s.RconPassword = ""
+		s.Rcon = nil
+		s.QueryAddress = ""
+	}
+
+	return nil
 }
 
 type GetAllServerResponse struct {
-	ServerID   string `json:"server_id"`
+	ServerID    string            `json:"server_id"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+
 	Status     string `json:"status"`
 	Address    string `json:"address,omitempty"`
 	OnlineMode bool   `json:"online_mode,omitempty"`
 	WorldName  string `json:"world_name,omitempty"`
 	LastError  string `json:"last_error,omitempty"`
+
+	RestartCount   int    `json:"restart_count"`
+	LastExitCode   *int   `json:"last_exit_code,omitempty"`
+	LastExitReason string `json:"last_exit_reason,omitempty"`
+	LastExitAt     string `json:"last_exit_at,omitempty"`
+	NextRestartAt  string `json:"next_restart_at,omitempty"`
+
+	LastStopOutcome string `json:"last_stop_outcome,omitempty"`
+	IsRestarting    bool   `json:"is_restarting,omitempty"`
+
+	IsRestartRequired bool   `json:"is_restart_required,omitempty"`
+	IsBackingUp       bool   `json:"is_backing_up,omitempty"`
+	LastBackupAt      string `json:"last_backup_at,omitempty"`
+
+	// Ping is the server list ping answer of a running server
+	Ping *slp.Status `json:"ping,omitempty"`
+	// Metrics is the latest sample of the process of an active server
+	Metrics *ProcessMetrics `json:"metrics,omitempty"`
 }
 
 func (gasr *GetAllServerResponse) GetLastError(id string) string {
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+)
+
+const (
+	// FILE_TEMPLATE_CONFIG is the definition of a template, stored inside the template folder
+	FILE_TEMPLATE_CONFIG = "msa-template.json"
+
+	// DIR_TEMPLATE_PLUGINS is copied into new servers as their plugins folder
+	DIR_TEMPLATE_PLUGINS = "plugins"
+)
+
+var templateNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.\-]{0,63}$`)
+
+func ValidateTemplateName(name string) error {
+	if name == "" {
+		return errors.New("template name cannot be empty")
+	}
+	if !templateNameRegex.MatchString(name) {
+		return errors.New("template name must be letters, digits, dots, dashes or underscores up to 64 characters")
+	}
+
+	return nil
+}
+
+// Template is the blueprint new servers are created from, the template folder may also hold a seed world and a
+// plugins folder that are copied into every server made from it
+type Template struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Jar falls back to DEFAULT_SERVER_JAR when empty
+	Jar    string       `json:"jar"`
+	RamGB  int          `json:"ram_gb"`
+	Launch LaunchConfig `json:"launch"`
+	// Properties seed the server.properties of new servers, keys managed by the api cannot be set
+	Properties map[string]string `json:"properties"`
+	// IsEulaAgreed agrees to the eula of new servers so they can start right away
+	IsEulaAgreed bool `json:"is_eula_agreed"`
+
+	// HasWorld and HasPlugins tell what the template folder holds, they are read from disk
+	HasWorld   bool `json:"has_world"`
+	HasPlugins bool `json:"has_plugins"`
+}
+
+// Normalize fills the defaults of the fields left empty
+func (t Template) Normalize() Template {
+	if t.Jar == "" {
+		t.Jar = DEFAULT_SERVER_JAR
+	}
+
+	if t.Launch.Profile == "" {
+		t.Launch.Profile = DEFAULT_LAUNCH_PROFILE
+	}
+	if t.Launch.ExtraJVMFlags == nil {
+		t.Launch.ExtraJVMFlags = []string{}
+	}
+	if t.Launch.SystemProperties == nil {
+		t.Launch.SystemProperties = map[string]string{}
+	}
+	if t.Launch.ExtraServerArgs == nil {
+		t.Launch.ExtraServerArgs = []string{}
+	}
+
+	if t.Properties == nil {
+		t.Properties = map[string]string{}
+	}
+
+	return t
+}
+
+// Validate checks the template, version is the minecraft version of its jar and checks the properties against it
+func (t Template) Validate(version string) error {
+	if err := ValidateTemplateName(t.Name); err != nil {
+		return err
+	}
+
+	if err := ValidateJarName(t.Jar); err != nil {
+		return err
+	}
+
+	if t.RamGB < 0 {
+		return errors.New("ram_gb cannot < 0")
+	}
+
+	if err := t.Launch.Validate(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(t.Properties))
+	for key := range t.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := ValidateProperty(version, key, t.Properties[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetServerConfig returns the config of a new server made from the template
+func (t Template) GetServerConfig() ServerConfig {
+	res := NewDefaultServerConfig()
+	res.Jar = t.Jar
+	res.RamGB = t.RamGB
+	res.Launch = t.Launch
+	// the seed world is copied under level-name, pin it so the server launches that world
+	res.WorldName = t.Properties["level-name"]
+
+	return res
+}
+
+// GetWorldName returns the world folder the seed world is copied to, level-name overrides the default one
+func (t Template) GetWorldName() string {
+	if name := t.Properties["level-name"]; name != "" {
+		return name
+	}
+
+	return DEFAULT_WORLD_NAME
+}
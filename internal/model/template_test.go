@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestTemplateWorldName(t *testing.T) {
+	tests := []struct {
+		name          string
+		properties    map[string]string
+		wantWorld     string
+		wantWorldName string
+	}{
+		{name: "default world", properties: map[string]string{}, wantWorld: DEFAULT_WORLD_NAME, wantWorldName: ""},
+		{name: "other properties", properties: map[string]string{"motd": "hi"}, wantWorld: DEFAULT_WORLD_NAME, wantWorldName: ""},
+		{name: "level-name", properties: map[string]string{"level-name": "lobby"}, wantWorld: "lobby", wantWorldName: "lobby"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := Template{Name: "test", Properties: tt.properties}
+
+			if got := template.GetWorldName(); got != tt.wantWorld {
+				t.Errorf("GetWorldName() = %q, want %q", got, tt.wantWorld)
+			}
+			if got := template.GetServerConfig().WorldName; got != tt.wantWorldName {
+				t.Errorf("GetServerConfig().WorldName = %q, want %q", got, tt.wantWorldName)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	MAX_JAR_UPLOAD_SIZE   = 512 << 20
+	MAX_WORLD_UPLOAD_SIZE = 4 << 30
+	// MAX_WORLD_EXTRACTED_SIZE caps the unpacked world so a small archive cannot fill the disk
+	MAX_WORLD_EXTRACTED_SIZE = 16 << 30
+)
+
+type UploadRequest struct {
+	// SHA256 is the expected checksum of the upload, the upload is rejected when it differs
+	SHA256      string
+	IsOverwrite bool
+}
+
+func (ur UploadRequest) Validate() error {
+	if ur.SHA256 == "" {
+		return nil
+	}
+
+	if v, err := hex.DecodeString(ur.SHA256); err != nil || len(v) != 32 {
+		return errors.New("sha256 must be 64 hex characters")
+	}
+
+	return nil
+}
+
+// IsChecksumMatch compares the expected checksum, if any, case-insensitively
+func (ur UploadRequest) IsChecksumMatch(sum string) bool {
+	return ur.SHA256 == "" || strings.EqualFold(ur.SHA256, sum)
+}
+
+type UploadWorldResponse struct {
+	WorldName string `json:"world_name"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// DEFAULT_WORLD_NAME is the level-name the server falls back to
+const DEFAULT_WORLD_NAME = "world"
+
+// ValidateWorldName makes sure the world is a plain folder directly inside the server folder
+func ValidateWorldName(name string) error {
+	if name == "" {
+		return errors.New("world_name cannot be empty")
+	}
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return errors.New("world_name must be a folder name inside the server folder")
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/archive"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+)
+
+const (
+	// backupSaveTimeout bounds how long a running server may take to flush its worlds
+	backupSaveTimeout = time.Minute * 2
+	// backupScheduleInterval is how often the scheduler looks for servers due for a backup
+	backupScheduleInterval = time.Minute
+)
+
+var backupSavedRegex = regexp.MustCompile(`Saved the game`)
+
+func getServerBackupDir(id string) string {
+	return path.Join(model.DIR_BACKUP, id)
+}
+
+func getBackupArchivePath(id, backupID string) string {
+	return path.Join(getServerBackupDir(id), backupID+model.BACKUP_ARCHIVE_EXT)
+}
+
+// getServerWorlds returns the world folders of the server that exist, the overworld along with the nether and end
+// folders servers like spigot keep next to it
+func getServerWorlds(id string, config model.ServerConfig) []string {
+	name := config.WorldName
+	if name == "" {
+		if props, err := properties.Load(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES)); err == nil {
+			name, _ = props.Get("level-name")
+		}
+	}
+	if name == "" {
+		name = model.DEFAULT_WORLD_NAME
+	}
+	// level-name is edited by hand too, never follow it out of the server folder
+	if err := model.ValidateWorldName(name); err != nil {
+		fmt.Printf("skip worlds of server %v: %v\n", id, err)
+		return nil
+	}
+
+	var res []string
+	for _, world := range []string{name, name + "_nether", name + "_the_end"} {
+		if pkg.IsFileOrFolderExist(path.Join(model.DIR_SERVER, id, world, "level.dat")) {
+			res = append(res, world)
+		}
+	}
+
+	return res
+}
+
+// readBackups returns the backups of the server from their manifests, newest first
+func readBackups(id string) ([]model.Backup, error) {
+	files, err := ioutil.ReadDir(getServerBackupDir(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.Backup{}, nil
+		}
+		return nil, err
+	}
+
+	res := []model.Backup{}
+	for _, f := range files {
+		backupID := strings.TrimSuffix(f.Name(), model.BACKUP_MANIFEST_EXT)
+		if f.IsDir() || model.ValidateBackupID(backupID) != nil || backupID+model.BACKUP_MANIFEST_EXT != f.Name() {
+			continue
+		}
+
+		backup, err := readBackup(id, backupID)
+		if err != nil {
+			fmt.Printf("fail to read backup %v of server %v: %v\n", backupID, id, err)
+			continue
+		}
+		res = append(res, backup)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].CreatedAt.After(res[j].CreatedAt)
+	})
+
+	return res, nil
+}
+
+func readBackup(id, backupID string) (model.Backup, error) {
+	var res model.Backup
+	if err := model.ValidateBackupID(backupID); err != nil {
+		return res, err
+	}
+
+	data, err := ioutil.ReadFile(path.Join(getServerBackupDir(id), backupID+model.BACKUP_MANIFEST_EXT))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, fmt.Errorf("backup %v not found", backupID)
+		}
+		return res, err
+	}
+
+	return res, json.Unmarshal(data, &res)
+}
+
+func newBackupID(now time.Time) (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return now.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(buf), nil
+}
+
+// createBackup archives the worlds of the server, a running server stops writing its worlds until the archive is done
+func (sr *serverResource) createBackup(id string, trigger model.BackupTrigger) (model.Backup, error) {
+	var srv model.Server
+	err := sr.updateServer(id, func(s *model.Server) error {
+		if s.IsBackingUp {
+			return errors.New("backup already in progress")
+		}
+		switch s.Status {
+		case model.ServerStatusStarting:
+			return errors.New("server is starting")
+		case model.ServerStatusStopping:
+			return errors.New("server is stopping")
+		}
+
+		s.IsBackingUp = true
+		srv = *s
+		return nil
+	})
+	if err != nil {
+		return model.Backup{}, err
+	}
+	defer sr.updateServer(id, func(s *model.Server) error {
+		s.IsBackingUp = false
+		return nil
+	})
+
+	worlds := getServerWorlds(id, srv.Config)
+	if len(worlds) == 0 {
+		return model.Backup{}, errors.New("server has no world to back up")
+	}
+
+	now := time.Now()
+	backupID, err := newBackupID(now)
+	if err != nil {
+		return model.Backup{}, err
+	}
+
+	res := model.Backup{
+		ID:        backupID,
+		ServerID:  id,
+		CreatedAt: now,
+		Trigger:   trigger,
+		Worlds:    worlds,
+		Jar:       srv.Config.Jar,
+		Version:   sr.getServerVersion(srv.Config),
+		IsOnline:  srv.Status == model.ServerStatusRunning,
+	}
+
+	if res.IsOnline {
+		resume, err := sr.pauseServerSaving(id)
+		if err != nil {
+			return model.Backup{}, err
+		}
+		defer resume()
+	}
+
+	if err := writeBackup(id, &res); err != nil {
+		return model.Backup{}, err
+	}
+
+	sr.updateServer(id, func(s *model.Server) error {
+		s.LastBackupAt = now
+		return nil
+	})
+
+	if err := sr.applyBackupRetention(id, srv.Config.BackupPolicy); err != nil {
+		fmt.Printf("fail to apply backup retention of server %v: %v\n", id, err)
+	}
+
+	return res, nil
+}
+
+// pauseServerSaving flushes the worlds of a running server to disk and keeps it from writing them until resume is
+// called, so the world folders can be copied consistently
+func (sr *serverResource) pauseServerSaving(id string) (func(), error) {
+	if _, err := sr.AddServerConsoleResource(id, "save-off"); err != nil {
+		return nil, err
+	}
+
+	resume := func() {
+		if _, err := sr.AddServerConsoleResource(id, "save-on"); err != nil {
+			fmt.Printf("fail to resume saving of server %v: %v\n", id, err)
+		}
+	}
+
+	saved, err := sr.ExecuteServerConsoleResource(id, "save-all flush", backupSaveTimeout, backupSavedRegex)
+	if err != nil {
+		resume()
+		return nil, err
+	}
+	if !saved.IsMatched {
+		resume()
+		return nil, errors.New("server did not confirm saving the worlds")
+	}
+
+	return resume, nil
+}
+
+// writeBackup archives the worlds with the manifest inside, then stores the manifest with the checksum of the archive
+// next to it, the manifest only shows up once the archive is complete
+func writeBackup(id string, backup *model.Backup) error {
+	backupDir := getServerBackupDir(id)
+	if err := pkg.ValidateDir(true, backupDir); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(backupDir, ".backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	err = archive.WriteTarGz(io.MultiWriter(tmp, h), path.Join(model.DIR_SERVER, id), backup.Worlds,
+		map[string][]byte{model.FILE_BACKUP_MANIFEST: manifest},
+		func(rel string) bool {
+			// the lock is held by the running server and means nothing to a restored world
+			return path.Base(rel) == "session.lock"
+		})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return err
+	}
+	backup.Size = info.Size()
+	backup.SHA256 = hex.EncodeToString(h.Sum(nil))
+
+	if err := os.Rename(tmp.Name(), getBackupArchivePath(id, backup.ID)); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	manifestPath := path.Join(backupDir, backup.ID+model.BACKUP_MANIFEST_EXT)
+	if err := ioutil.WriteFile(manifestPath+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(manifestPath+".tmp", manifestPath)
+}
+
+func deleteBackup(id, backupID string) error {
+	backupDir := getServerBackupDir(id)
+
+	// drop the manifest first so a half deleted backup is never listed
+	if err := os.Remove(path.Join(backupDir, backupID+model.BACKUP_MANIFEST_EXT)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("backup %v not found", backupID)
+		}
+		return err
+	}
+
+	if err := os.Remove(getBackupArchivePath(id, backupID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *serverResource) applyBackupRetention(id string, policy model.BackupPolicy) error {
+	backups, err := readBackups(id)
+	if err != nil {
+		return err
+	}
+
+	var rotated []model.Backup
+	for _, backup := range backups {
+		if backup.Trigger != model.BackupTriggerPreRestore {
+			rotated = append(rotated, backup)
+		}
+	}
+
+	for _, backup := range policy.GetExpiredBackups(rotated) {
+		if err := deleteBackup(id, backup.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runBackupScheduler backs up every running server whose backup interval has passed since its latest backup
+func (sr *serverResource) runBackupScheduler() {
+	ticker := time.NewTicker(backupScheduleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		servers := sr.getAllServer()
+		for id, srv := range servers {
+			interval := time.Duration(srv.Config.BackupPolicy.IntervalMinutes) * time.Minute
+			if interval == 0 || srv.Status != model.ServerStatusRunning || srv.IsBackingUp {
+				continue
+			}
+
+			if time.Since(srv.LastBackupAt) < interval {
+				continue
+			}
+
+			go func(id string) {
+				if _, err := sr.createBackup(id, model.BackupTriggerScheduled); err != nil {
+					fmt.Printf("fail to back up server %v: %v\n", id, err)
+				}
+			}(id)
+		}
+	}
+}
+
+func (sr *serverResource) CreateServerBackupResource(id string) (model.Backup, error) {
+	return sr.createBackup(id, model.BackupTriggerManual)
+}
+
+func (sr *serverResource) GetAllServerBackupResource(id string) ([]model.Backup, error) {
+	if _, err := sr.getServer(id); err != nil {
+		return nil, err
+	}
+
+	return readBackups(id)
+}
+
+func (sr *serverResource) DeleteServerBackupResource(id, backupID string) error {
+	if _, err := sr.getServer(id); err != nil {
+		return err
+	}
+
+	if err := model.ValidateBackupID(backupID); err != nil {
+		return err
+	}
+
+	return deleteBackup(id, backupID)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+
+	"github.com/google/uuid"
+)
+
+// isCloneSkipped leaves out what belongs to the source server only, its console output, pid, config, lock files and
+// the staging folders of running uploads and restores
+func isCloneSkipped(rel string) bool {
+	switch rel {
+	case "msa.std", model.FILE_SERVER_PID, model.FILE_SERVER_CONFIG:
+		return true
+	}
+
+	base := path.Base(rel)
+	return base == "session.lock" || strings.HasPrefix(base, ".")
+}
+
+// CloneServerResource copies the server folder into a new server listening on its own port, a running source keeps
+// its worlds unwritten while they are copied
+func (sr *serverResource) CloneServerResource(id, name string, port int) (string, error) {
+	// hold the source like a backup does for the whole copy, so no other backup resumes its saving and no start,
+	// delete, restore or upload changes its folder meanwhile
+	var srv model.Server
+	err := sr.updateServer(id, func(s *model.Server) error {
+		if s.IsBackingUp {
+			return errors.New("server is backing up")
+		}
+		if s.IsRestoring {
+			return errors.New("server is restoring a backup")
+		}
+		switch s.Status {
+		case model.ServerStatusStarting:
+			return errors.New("server is starting")
+		case model.ServerStatusStopping:
+			return errors.New("server is stopping")
+		}
+
+		s.IsBackingUp = true
+		srv = *s
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	defer sr.updateServer(id, func(s *model.Server) error {
+		s.IsBackingUp = false
+		return nil
+	})
+
+	// round trip the config so the clone shares no map or slice with the source
+	var config model.ServerConfig
+	data, err := json.Marshal(srv.Config)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", err
+	}
+
+	if name == "" && srv.Config.Name != "" {
+		name = sr.findFreeServerName(srv.Config.Name + "-copy")
+	}
+	if err := model.ValidateServerName(name); err != nil {
+		return "", err
+	}
+	config.Name = name
+
+	// derive rcon and query from the new port instead of sharing the ones of the source
+	config.RconPort = 0
+	config.QueryPort = 0
+
+	config.Port = port
+	if config.Port == 0 {
+		if config.Port, err = sr.findFreePort(config); err != nil {
+			return "", err
+		}
+	} else {
+		// fail before copying, the registration checks again once the folder is in place
+		sr.mu.Lock()
+		err = sr.checkPortsFree(config, "")
+		sr.mu.Unlock()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if srv.Status == model.ServerStatusRunning {
+		resume, err := sr.pauseServerSaving(id)
+		if err != nil {
+			return "", err
+		}
+		defer resume()
+	}
+
+	var resID = uuid.New().String()
+	dst := path.Join(model.DIR_SERVER, resID)
+	if err := pkg.CopyDirWithFilter(path.Join(model.DIR_SERVER, id), dst, isCloneSkipped); err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+
+	if err := resetClonedProperties(resID); err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+
+	if err := writeServerConfig(resID, config); err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+
+	if err := sr.addServer(resID, config); err != nil {
+		os.RemoveAll(dst)
+		return "", err
+	}
+
+	return resID, nil
+}
+
+// findFreeServerName returns base, or base followed by the lowest free number when base is taken, an empty name when
+// the result is not a valid name
+func (sr *serverResource) findFreeServerName(base string) string {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	res := base
+	for i := 2; sr.checkServerNameFree(res, "") != nil; i++ {
+		res = fmt.Sprintf("%v-%v", base, i)
+	}
+
+	if model.ValidateServerName(res) != nil {
+		return ""
+	}
+
+	return res
+}
+
+// resetClonedProperties drops the rcon password of the source so the clone gets its own on first launch
+func resetClonedProperties(id string) error {
+	filePath := path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES)
+	if !pkg.IsFileOrFolderExist(filePath) {
+		return nil
+	}
+
+	props, err := properties.Load(filePath)
+	if err != nil {
+		return err
+	}
+	props.Delete("rcon.password")
+
+	return props.Save(filePath)
+}
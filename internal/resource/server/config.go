@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+)
+
+// readServerConfig loads msa.json of the server, falling back to the default config when it is missing
+func readServerConfig(id string) (model.ServerConfig, error) {
+	var res = model.NewDefaultServerConfig()
+
+	data, err := ioutil.ReadFile(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_CONFIG))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+		return res, err
+	}
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		return res, err
+	}
+
+	if res.Metadata == nil {
+		res.Metadata = map[string]string{}
+	}
+	if res.Labels == nil {
+		res.Labels = map[string]string{}
+	}
+
+	// configs written before launch profiles carry the aikar flags the default profile already has
+	if reflect.DeepEqual(res.JVMFlags, model.DEFAULT_JVM_FLAGS) {
+		res.JVMFlags = nil
+	}
+	if res.Launch.ExtraJVMFlags == nil {
+		res.Launch.ExtraJVMFlags = []string{}
+	}
+	if res.Launch.SystemProperties == nil {
+		res.Launch.SystemProperties = map[string]string{}
+	}
+	if res.Launch.ExtraServerArgs == nil {
+		res.Launch.ExtraServerArgs = []string{}
+	}
+
+	return res, nil
+}
+
+// writeServerConfig replaces msa.json of the server through a temporary file so a crash never leaves it half written
+func writeServerConfig(id string, config model.ServerConfig) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filePath := path.Join(model.DIR_SERVER, id, model.FILE_SERVER_CONFIG)
+	if err := ioutil.WriteFile(filePath+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(filePath+".tmp", filePath)
+}
+
+func (sr *serverResource) GetServerConfigResource(id string) (model.ServerConfig, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.ServerConfig{}, err
+	}
+
+	return srv.Config, nil
+}
+
+func (sr *serverResource) UpdateServerConfigResource(id string, req model.UpdateServerConfigRequest) (model.ServerConfig, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.ServerConfig{}, err
+	}
+
+	// resolve the version into a catalog jar, sticking to the flavor the server runs today
+	if req.Version != nil {
+		if req.Jar != nil {
+			return model.ServerConfig{}, errors.New("jar and version cannot be set together")
+		}
+
+		var flavor model.JarFlavor
+		if jar, err := sr.readJar(srv.Config.Jar); err == nil {
+			flavor = jar.Flavor
+		}
+
+		name, err := sr.findJarByVersion(*req.Version, flavor)
+		if err != nil {
+			return model.ServerConfig{}, err
+		}
+		req.Jar = &name
+	} else if req.Jar != nil {
+		if _, err := sr.readJar(*req.Jar); err != nil {
+			return model.ServerConfig{}, err
+		}
+	}
+
+	if req.Runtime != nil && *req.Runtime != "" {
+		if _, err := sr.findRuntime(*req.Runtime); err != nil {
+			return model.ServerConfig{}, err
+		}
+	}
+
+	var res model.ServerConfig
+	err = sr.updateServer(id, func(s *model.Server) error {
+		config, err := req.Apply(s.Config)
+		if err != nil {
+			return err
+		}
+
+		if err := sr.checkServerNameFree(config.Name, id); err != nil {
+			return err
+		}
+		if err := sr.checkPortsFree(config, id); err != nil {
+			return err
+		}
+
+		if err := writeServerConfig(id, config); err != nil {
+			return err
+		}
+
+		s.Config = config
+		res = config
+		return nil
+	})
+
+	return res, err
+}
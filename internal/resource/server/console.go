@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+const consoleBacklogSize = 1000
+
+// tailServerOutput publishes every line of msa.std until the process exits, the server writes straight into
+// the file instead of a pipe so that it keeps logging when the api goes away
+func tailServerOutput(id string, console *pkg.Broadcaster, exited <-chan struct{}) {
+	console.Reset()
+
+	fileOut, err := os.Open(path.Join(model.DIR_SERVER, id, "msa.std"))
+	if err != nil {
+		return
+	}
+	defer fileOut.Close()
+
+	reader := bufio.NewReader(fileOut)
+	ticker := time.NewTicker(time.Millisecond * 200)
+	defer ticker.Stop()
+
+	var partial string
+	for {
+		isExited := false
+		select {
+		case <-exited:
+			isExited = true
+		case <-ticker.C:
+		}
+
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				partial += line
+				break
+			}
+
+			console.Publish(strings.TrimRight(partial+line, "\r\n"))
+			partial = ""
+		}
+
+		if isExited {
+			if partial != "" {
+				console.Publish(partial)
+			}
+			return
+		}
+	}
+}
+
+func (sr *serverResource) SubscribeServerConsoleResource(id string, backlog int) (*pkg.Subscription, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return srv.Console.Subscribe(backlog), nil
+}
+
+// ExecuteServerConsoleResource sends the command and collects the console lines that follow it until the wait
+// window ends or a line matches until
+func (sr *serverResource) ExecuteServerConsoleResource(id, command string, wait time.Duration, until *regexp.Regexp) (model.ExecuteCommandResponse, error) {
+	var res = model.ExecuteCommandResponse{
+		Command: command,
+		Output:  []string{},
+	}
+
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return res, err
+	}
+
+	// subscribe first so that no line written right after the command is missed
+	sub := srv.Console.Subscribe(0)
+	defer sub.Close()
+
+	output, err := sr.AddServerConsoleResource(id, command)
+	if err != nil {
+		return res, err
+	}
+
+	// rcon already answered with the output of the command
+	if output != "" {
+		res.Output = strings.Split(strings.TrimRight(output, "\n"), "\n")
+		if until != nil {
+			res.IsMatched = until.MatchString(output)
+		}
+		return res, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	isEchoSkipped := false
+	for {
+		select {
+		case <-timer.C:
+			return res, nil
+		case line, ok := <-sub.Lines:
+			if !ok {
+				return res, nil
+			}
+
+			// the command itself is echoed into the console
+			if !isEchoSkipped && line == command {
+				isEchoSkipped = true
+				continue
+			}
+
+			res.Output = append(res.Output, line)
+			if until != nil && until.MatchString(line) {
+				res.IsMatched = true
+				return res, nil
+			}
+		}
+	}
+}
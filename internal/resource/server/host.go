@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+// getCommitments returns the maximum heap of every active server but except, the caller holds sr.mu
+func (sr *serverResource) getCommitments(except string) map[string]int {
+	res := make(map[string]int)
+	for id, srv := range sr.serverdata {
+		if id == except || !srv.Status.IsActive() {
+			continue
+		}
+
+		// the config may have changed since the server was admitted, count the heap it was launched with
+		xmx := srv.LaunchedXmxMB
+		if xmx == 0 {
+			_, xmx = srv.Config.GetHeapMB()
+		}
+		res[id] = xmx
+	}
+
+	return res
+}
+
+// getHostMemory accounts the memory of the host against the servers but except, the caller holds sr.mu
+func (sr *serverResource) getHostMemory(except string) (model.HostMemory, map[string]int, error) {
+	info, err := pkg.GetMemoryInfo()
+	if err != nil {
+		return model.HostMemory{}, nil, fmt.Errorf("cannot read the memory of the host: %v", err)
+	}
+
+	commitments := sr.getCommitments(except)
+
+	res := model.HostMemory{
+		TotalMB:     int(info.TotalBytes / 1024 / 1024),
+		ReserveMB:   sr.opt.MemoryReserveMB,
+		AvailableMB: int(info.AvailableBytes / 1024 / 1024),
+	}
+	res.CapacityMB = res.TotalMB - res.ReserveMB
+	if res.CapacityMB < 0 {
+		res.CapacityMB = 0
+	}
+	for _, mb := range commitments {
+		res.CommittedMB += mb
+	}
+	res.FreeMB = res.CapacityMB - res.CommittedMB
+	if res.FreeMB < 0 {
+		res.FreeMB = 0
+	}
+
+	return res, commitments, nil
+}
+
+// checkMemoryAdmission fails when the heap of the server does not fit in what the other active servers left free,
+// the caller holds sr.mu
+func (sr *serverResource) checkMemoryAdmission(id string, config model.ServerConfig) error {
+	mem, _, err := sr.getHostMemory(id)
+	if err != nil {
+		return err
+	}
+
+	_, xmx := config.GetHeapMB()
+	if xmx > mem.FreeMB {
+		return fmt.Errorf("not enough memory, server needs %vMB but only %vMB of %vMB is free", xmx, mem.FreeMB, mem.CapacityMB)
+	}
+
+	return nil
+}
+
+func (sr *serverResource) GetHostResource() (model.GetHostResponse, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	mem, commitments, err := sr.getHostMemory("")
+	if err != nil {
+		return model.GetHostResponse{}, err
+	}
+
+	return model.GetHostResponse{
+		CPUCount:    runtime.NumCPU(),
+		Memory:      mem,
+		Commitments: commitments,
+	}, nil
+}
@@ -3,58 +3,203 @@ package server
 import (
 	"errors"
 	"fmt"
+	"path"
+	"strings"
+	"sync"
 
 	"github.com/Bearaujus/minecraft-server-api/internal/model"
 	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/java"
 )
 
+// serverTrashPrefix marks server folders that are being deleted, they are hidden from the registry
+const serverTrashPrefix = ".deleted-"
+
+type Options struct {
+	// OrphanPolicy decides whether servers still running from a previous run are re-adopted or killed
+	OrphanPolicy model.OrphanPolicy
+	// JavaDirs are scanned for java runtimes, each sub folder being a java home
+	JavaDirs []string
+	// MemoryReserveMB is kept free for the host, servers are refused to start when their heap would eat into it
+	MemoryReserveMB int
+}
+
 type serverResource struct {
+	mu         sync.Mutex
+	opt        Options
 	serverdata map[string]*model.Server
+
+	jarMu    sync.Mutex
+	jarCache map[string]jarCacheEntry
+
+	// templateMu keeps templates from changing while servers are made from them
+	templateMu sync.Mutex
+
+	metricsMu sync.Mutex
+	metrics   map[string]*metricsHistory
 }
 
-func NewServerResource() ServerResourceItf {
+func NewServerResource(opt Options) ServerResourceItf {
+	if opt.OrphanPolicy == "" {
+		opt.OrphanPolicy = model.OrphanPolicyAdopt
+	}
+	if opt.JavaDirs == nil {
+		opt.JavaDirs = java.DEFAULT_DIRS
+	}
+
 	var res = &serverResource{
+		opt:        opt,
 		serverdata: make(map[string]*model.Server),
+		jarCache:   make(map[string]jarCacheEntry),
+		metrics:    make(map[string]*metricsHistory),
 	}
 
+	// recovered servers get supervised right away, keep them waiting until the registry is complete
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
 	pkg.ValidateDir(true, model.DIR_SERVER)
 	var modelServerID, _ = pkg.GetListFolderFromDir(model.DIR_SERVER)
 	for _, id := range modelServerID {
-		res.serverdata[id] = nil
+		// finish deletes cut short by a previous run
+		if strings.HasPrefix(id, serverTrashPrefix) {
+			if err := pkg.DeleteDir(path.Join(model.DIR_SERVER, id)); err != nil {
+				fmt.Printf("fail to remove deleted server folder %v: %v\n", id, err)
+			}
+			continue
+		}
+
+		status := model.ServerStatusCreated
+		if pkg.IsFileOrFolderExist(path.Join(model.DIR_SERVER, id, "msa.std")) {
+			status = model.ServerStatusStopped
+		}
+
+		config, err := readServerConfig(id)
+		if err != nil {
+			fmt.Printf("fail to read config of server %v: %v\n", id, err)
+		}
+
+		res.serverdata[id] = &model.Server{
+			ID:      id,
+			Status:  status,
+			Config:  config,
+			Console: pkg.NewBroadcaster(consoleBacklogSize),
+		}
+
+		if backups, err := readBackups(id); err == nil && len(backups) > 0 {
+			res.serverdata[id].LastBackupAt = backups[0].CreatedAt
+		}
+
+		res.recoverServer(id)
 	}
 
+	go res.runBackupScheduler()
+	go res.runMetricsSampler()
+
 	return res
 }
 
-func (sr *serverResource) addServer(id string) error {
+func (sr *serverResource) addServer(id string, config model.ServerConfig) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
 	var _, ok = sr.serverdata[id]
 	if ok {
 		return errors.New("server already exist")
 	}
-	sr.serverdata[id] = nil
+	if err := sr.checkServerNameFree(config.Name, id); err != nil {
+		return err
+	}
+	if err := sr.checkPortsFree(config, id); err != nil {
+		return err
+	}
+	sr.serverdata[id] = &model.Server{
+		ID:      id,
+		Status:  model.ServerStatusCreated,
+		Config:  config,
+		Console: pkg.NewBroadcaster(consoleBacklogSize),
+	}
+
+	return nil
+}
+
+// checkServerNameFree fails when a server other than except goes by the name, the caller holds sr.mu
+func (sr *serverResource) checkServerNameFree(name, except string) error {
+	if name == "" {
+		return nil
+	}
+
+	for id, srv := range sr.serverdata {
+		if id != except && srv.Config.Name == name {
+			return fmt.Errorf("name %v is already used by server %v", name, id)
+		}
+	}
 
 	return nil
 }
 
-func (sr *serverResource) getServer(id string) (*model.Server, error) {
+// ResolveServerIDResource returns the id of the server going by idOrName, ids win over names
+func (sr *serverResource) ResolveServerIDResource(idOrName string) (string, error) {
+	if idOrName == "" {
+		return "", errors.New("id is required")
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if _, ok := sr.serverdata[idOrName]; ok {
+		return idOrName, nil
+	}
+
+	for id, srv := range sr.serverdata {
+		if srv.Config.Name == idOrName {
+			return id, nil
+		}
+	}
+
+	return "", errors.New("server not exist")
+}
+
+// getServer returns a snapshot of the server, changes must go through updateServer
+func (sr *serverResource) getServer(id string) (model.Server, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
 	var res, ok = sr.serverdata[id]
 	if !ok {
-		return nil, errors.New("server not exist")
+		return model.Server{}, errors.New("server not exist")
 	}
 
-	if res != nil {
-		// kill server if pipe is broken
-		if _, err := fmt.Fprint(*res.StdinPipe, ""); err != nil {
-			res.Cmd.Process.Kill()
-			sr.serverdata[id] = nil
-		}
+	return *res, nil
+}
+
+func (sr *serverResource) getAllServer() map[string]model.Server {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	var res = make(map[string]model.Server, len(sr.serverdata))
+	for k, v := range sr.serverdata {
+		res[k] = *v
 	}
 
-	return res, nil
+	return res
 }
 
-func (sr *serverResource) setServer(id string, server *model.Server) error {
-	sr.serverdata[id] = server
+// updateServer runs fn against the stored server while holding the registry lock
+func (sr *serverResource) updateServer(id string, fn func(*model.Server) error) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
 
-	return nil
+	var res, ok = sr.serverdata[id]
+	if !ok {
+		return errors.New("server not exist")
+	}
+
+	return fn(res)
+}
+
+func (sr *serverResource) setServerStatus(id string, status model.ServerStatus) error {
+	return sr.updateServer(id, func(s *model.Server) error {
+		return s.SetStatus(status)
+	})
 }
@@ -1,14 +1,55 @@
 package server
 
-import "github.com/Bearaujus/minecraft-server-api/internal/model"
+import (
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/java"
+	"github.com/Bearaujus/minecraft-server-api/pkg/query"
+	"github.com/Bearaujus/minecraft-server-api/pkg/slp"
+)
 
 type ServerResourceItf interface {
-	GetAllServerResource() (map[string]*model.Server, error)
-	CreateServerResource() (string, error)
+	GetAllServerResource(model.LabelSelector) (map[string]model.Server, error)
+	ResolveServerIDResource(string) (string, error)
+	CreateServerResource(string) (string, error)
+	CloneServerResource(string, string, int) (string, error)
 	DeleteServerResource(string) error
 	AgreeEulaServerResource(string) error
 	StartServerResource(string, int, int, string) error
-	StopServerResource(string) error
+	StopServerResource(string, model.StopServerRequest) (model.StopOutcome, error)
+	RestartServerResource(string) error
 	GetServerConsoleResource(string) ([]byte, error)
-	AddServerConsoleResource(string, string) error
+	AddServerConsoleResource(string, string) (string, error)
+	ExecuteServerConsoleResource(string, string, time.Duration, *regexp.Regexp) (model.ExecuteCommandResponse, error)
+	SubscribeServerConsoleResource(string, int) (*pkg.Subscription, error)
+	GetServerLogsResource(string, model.LogFilter) (model.GetServerLogsResponse, error)
+	PingServerResource(string) (*slp.Status, error)
+	QueryServerResource(string) (*query.FullStat, error)
+	GetServerMetricsResource(string) (model.GetServerMetricsResponse, error)
+	UploadServerWorldResource(string, string, io.Reader, model.UploadRequest) (model.UploadWorldResponse, error)
+	CreateServerBackupResource(string) (model.Backup, error)
+	GetAllServerBackupResource(string) ([]model.Backup, error)
+	DeleteServerBackupResource(string, string) error
+	RestoreServerBackupResource(string, string, bool) (model.RestoreBackupResponse, error)
+	GetServerPropertiesResource(string) (model.ServerPropertiesResponse, error)
+	UpdateServerPropertiesResource(string, model.UpdateServerPropertiesRequest) (model.ServerPropertiesResponse, error)
+	GetAllJarResource() ([]model.Jar, error)
+	GetJarResource(string) (model.Jar, error)
+	UploadJarResource(string, io.Reader, model.UploadRequest) (model.Jar, error)
+	DeleteJarResource(string) error
+	GetAllTemplateResource() ([]model.Template, error)
+	GetTemplateResource(string) (model.Template, error)
+	PutTemplateResource(string, model.Template) (model.Template, error)
+	DeleteTemplateResource(string) error
+	UploadTemplateWorldResource(string, io.Reader, model.UploadRequest) (model.Template, error)
+	UploadTemplatePluginsResource(string, io.Reader, model.UploadRequest) (model.Template, error)
+	GetAllLaunchProfileResource() (map[string][]string, error)
+	GetAllRuntimeResource() ([]java.Runtime, error)
+	GetHostResource() (model.GetHostResponse, error)
+	GetServerConfigResource(string) (model.ServerConfig, error)
+	UpdateServerConfigResource(string, model.UpdateServerConfigRequest) (model.ServerConfig, error)
 }
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+// jarCacheEntry keeps the inspected jar as long as the file keeps its size and modification time, hashing a jar
+// takes a while
+type jarCacheEntry struct {
+	size    int64
+	modTime time.Time
+	jar     model.Jar
+}
+
+// jarVersionFile is version.json found at the root of vanilla jars and most jars built on top of them
+type jarVersionFile struct {
+	ID          string `json:"id"`
+	JavaVersion int    `json:"java_version"`
+}
+
+// jarFlavorMarkers maps a path prefix inside the jar to the flavor it gives away, the first match wins
+var jarFlavorMarkers = []struct {
+	prefix string
+	flavor model.JarFlavor
+}{
+	{"io/papermc/", model.JarFlavorPaper},
+	{"com/destroystokyo/paper/", model.JarFlavorPaper},
+	{"net/fabricmc/", model.JarFlavorFabric},
+	{"net/minecraftforge/", model.JarFlavorForge},
+	{"org/bukkit/", model.JarFlavorSpigot},
+	{"net/minecraft/", model.JarFlavorVanilla},
+}
+
+// inspectJar reads the flavor and version of the jar, it fails when the file is not a jar archive
+func inspectJar(filePath string) (model.Jar, error) {
+	var res model.Jar
+
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return res, errors.New("file is not a jar archive")
+	}
+	defer zr.Close()
+
+	flavorRank := len(jarFlavorMarkers)
+	for _, f := range zr.File {
+		if f.Name == "version.json" {
+			rc, err := f.Open()
+			if err != nil {
+				return res, err
+			}
+			var vf jarVersionFile
+			err = json.NewDecoder(rc).Decode(&vf)
+			rc.Close()
+			if err == nil {
+				res.Version = vf.ID
+				res.JavaVersion = vf.JavaVersion
+			}
+			continue
+		}
+
+		for i := 0; i < flavorRank; i++ {
+			if strings.HasPrefix(f.Name, jarFlavorMarkers[i].prefix) {
+				flavorRank = i
+				break
+			}
+		}
+	}
+
+	res.Flavor = model.JarFlavorUnknown
+	if flavorRank < len(jarFlavorMarkers) {
+		res.Flavor = jarFlavorMarkers[flavorRank].flavor
+	} else if res.Version != "" {
+		// bundler jars of recent vanilla releases only carry the version file next to the bundler classes
+		res.Flavor = model.JarFlavorVanilla
+	}
+
+	return res, nil
+}
+
+// readJar returns the catalog entry of the jar without UsedBy
+func (sr *serverResource) readJar(name string) (model.Jar, error) {
+	if err := model.ValidateJarName(name); err != nil {
+		return model.Jar{}, err
+	}
+
+	filePath := path.Join(model.DIR_JAR, name)
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.Jar{}, fmt.Errorf("jar %v not found", name)
+		}
+		return model.Jar{}, err
+	}
+
+	sr.jarMu.Lock()
+	entry, ok := sr.jarCache[name]
+	sr.jarMu.Unlock()
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		return entry.jar, nil
+	}
+
+	res, err := inspectJar(filePath)
+	if err != nil {
+		return model.Jar{}, fmt.Errorf("jar %v: %v", name, err)
+	}
+
+	res.SHA256, err = pkg.GetFileSHA256(filePath)
+	if err != nil {
+		return model.Jar{}, err
+	}
+
+	res.Name = name
+	res.Size = info.Size()
+	res.ModifiedAt = info.ModTime().Format(time.RFC3339)
+	if res.Version == "" {
+		res.Version = model.ParseVersion(name)
+	}
+
+	sr.jarMu.Lock()
+	sr.jarCache[name] = jarCacheEntry{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		jar:     res,
+	}
+	sr.jarMu.Unlock()
+
+	return res, nil
+}
+
+// getJarUsers returns the servers configured to launch the jar
+func (sr *serverResource) getJarUsers(name string) []string {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	res := []string{}
+	for id, srv := range sr.serverdata {
+		if srv.Config.Jar == name {
+			res = append(res, id)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// getServerVersion is the release version of the jar the config launches, empty when it is unknown or a snapshot
+func (sr *serverResource) getServerVersion(config model.ServerConfig) string {
+	if jar, err := sr.readJar(config.Jar); err == nil {
+		return model.ParseVersion(jar.Version)
+	}
+
+	return config.GetVersion()
+}
+
+// findJarByVersion picks the catalog jar of the version, preferring the given flavor and then vanilla
+func (sr *serverResource) findJarByVersion(version string, flavor model.JarFlavor) (string, error) {
+	jars, err := sr.GetAllJarResource()
+	if err != nil {
+		return "", err
+	}
+
+	var res *model.Jar
+	var resScore int
+	for i, jar := range jars {
+		if jar.Version != version {
+			continue
+		}
+
+		score := 0
+		switch jar.Flavor {
+		case flavor:
+			score = 2
+		case model.JarFlavorVanilla:
+			score = 1
+		}
+
+		if res == nil || score > resScore {
+			res = &jars[i]
+			resScore = score
+		}
+	}
+
+	if res == nil {
+		return "", fmt.Errorf("no jar of version %v found", version)
+	}
+
+	return res.Name, nil
+}
+
+func (sr *serverResource) GetAllJarResource() ([]model.Jar, error) {
+	if err := pkg.ValidateDir(true, model.DIR_JAR); err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(model.DIR_JAR)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []model.Jar{}
+	for _, f := range files {
+		if f.IsDir() || model.ValidateJarName(f.Name()) != nil {
+			continue
+		}
+
+		jar, err := sr.readJar(f.Name())
+		if err != nil {
+			// keep listing the other jars, a broken one shows up as unknown
+			jar = model.Jar{
+				Name:       f.Name(),
+				Size:       f.Size(),
+				ModifiedAt: f.ModTime().Format(time.RFC3339),
+				Flavor:     model.JarFlavorUnknown,
+			}
+		}
+		jar.UsedBy = sr.getJarUsers(jar.Name)
+
+		res = append(res, jar)
+	}
+
+	return res, nil
+}
+
+func (sr *serverResource) GetJarResource(name string) (model.Jar, error) {
+	jar, err := sr.readJar(name)
+	if err != nil {
+		return jar, err
+	}
+	jar.UsedBy = sr.getJarUsers(name)
+
+	return jar, nil
+}
+
+// UploadJarResource streams the jar into the catalog through a temporary file, so a failed upload never leaves a
+// broken jar behind and servers running the previous file are not disturbed
+func (sr *serverResource) UploadJarResource(name string, body io.Reader, req model.UploadRequest) (model.Jar, error) {
+	if err := model.ValidateJarName(name); err != nil {
+		return model.Jar{}, err
+	}
+	if err := req.Validate(); err != nil {
+		return model.Jar{}, err
+	}
+
+	if err := pkg.ValidateDir(true, model.DIR_JAR); err != nil {
+		return model.Jar{}, err
+	}
+
+	filePath := path.Join(model.DIR_JAR, name)
+	if !req.IsOverwrite && pkg.IsFileOrFolderExist(filePath) {
+		return model.Jar{}, fmt.Errorf("jar %v already exists", name)
+	}
+
+	tmpPath, _, sum, err := receiveUpload(model.DIR_JAR, body, model.MAX_JAR_UPLOAD_SIZE, req)
+	if err != nil {
+		return model.Jar{}, err
+	}
+	defer os.Remove(tmpPath)
+
+	if _, err := inspectJar(tmpPath); err != nil {
+		return model.Jar{}, err
+	}
+
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return model.Jar{}, err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return model.Jar{}, err
+	}
+
+	jar, err := sr.GetJarResource(name)
+	if err != nil {
+		return jar, err
+	}
+
+	// the file may have been replaced again between the rename and the read
+	if jar.SHA256 != sum {
+		return jar, fmt.Errorf("jar %v changed during upload", name)
+	}
+
+	return jar, nil
+}
+
+func (sr *serverResource) DeleteJarResource(name string) error {
+	if err := model.ValidateJarName(name); err != nil {
+		return err
+	}
+
+	if users := sr.getJarUsers(name); len(users) > 0 {
+		return fmt.Errorf("jar %v is used by server %v", name, strings.Join(users, ", "))
+	}
+
+	if err := os.Remove(path.Join(model.DIR_JAR, name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("jar %v not found", name)
+		}
+		return err
+	}
+
+	sr.jarMu.Lock()
+	delete(sr.jarCache, name)
+	sr.jarMu.Unlock()
+
+	return nil
+}
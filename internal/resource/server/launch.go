@@ -0,0 +1,12 @@
+package server
+
+import "github.com/Bearaujus/minecraft-server-api/internal/model"
+
+func (sr *serverResource) GetAllLaunchProfileResource() (map[string][]string, error) {
+	res := make(map[string][]string, len(model.LAUNCH_PROFILES))
+	for name, flags := range model.LAUNCH_PROFILES {
+		res[name] = append([]string{}, flags...)
+	}
+
+	return res, nil
+}
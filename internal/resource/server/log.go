@@ -0,0 +1,44 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+)
+
+func (sr *serverResource) GetServerLogsResource(id string, filter model.LogFilter) (model.GetServerLogsResponse, error) {
+	var res = model.GetServerLogsResponse{
+		Entries:    []model.LogEntry{},
+		NextCursor: filter.Cursor,
+	}
+
+	if _, err := sr.getServer(id); err != nil {
+		return res, err
+	}
+
+	data, err := ioutil.ReadFile(path.Join(model.DIR_SERVER, id, "msa.std"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+		return res, err
+	}
+
+	for _, entry := range model.ParseLogLines(string(data)) {
+		if !filter.Match(entry) {
+			continue
+		}
+
+		if len(res.Entries) >= filter.Limit {
+			res.HasMore = true
+			break
+		}
+
+		res.Entries = append(res.Entries, entry)
+		res.NextCursor = entry.Line
+	}
+
+	return res, nil
+}
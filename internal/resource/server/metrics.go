@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+const (
+	// metricsSampleInterval is how often the process of every active server is sampled
+	metricsSampleInterval = time.Second * 5
+	// metricsHistorySize samples are kept per server, 10 minutes at the sample interval
+	metricsHistorySize = 120
+)
+
+// metricsHistory is a ring of the latest samples of a server process
+type metricsHistory struct {
+	pid     int
+	cpuTime time.Duration
+
+	samples []model.ProcessMetrics
+	next    int
+	count   int
+}
+
+func newMetricsHistory(pid int) *metricsHistory {
+	return &metricsHistory{
+		pid:     pid,
+		samples: make([]model.ProcessMetrics, metricsHistorySize),
+	}
+}
+
+func (mh *metricsHistory) add(sample model.ProcessMetrics) {
+	mh.samples[mh.next] = sample
+	mh.next = (mh.next + 1) % len(mh.samples)
+	if mh.count < len(mh.samples) {
+		mh.count++
+	}
+}
+
+// list returns the samples oldest first
+func (mh *metricsHistory) list() []model.ProcessMetrics {
+	res := make([]model.ProcessMetrics, 0, mh.count)
+	start := (mh.next - mh.count + len(mh.samples)) % len(mh.samples)
+	for i := 0; i < mh.count; i++ {
+		res = append(res, mh.samples[(start+i)%len(mh.samples)])
+	}
+
+	return res
+}
+
+func (mh *metricsHistory) latest() *model.ProcessMetrics {
+	if mh.count == 0 {
+		return nil
+	}
+
+	res := mh.samples[(mh.next-1+len(mh.samples))%len(mh.samples)]
+	return &res
+}
+
+// sampleServer reads the process of the server and records the sample, a new process starts a new history
+func (sr *serverResource) sampleServer(id string, pid int) error {
+	stat, err := pkg.GetProcessStat(pid)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	sr.metricsMu.Lock()
+	defer sr.metricsMu.Unlock()
+
+	history, ok := sr.metrics[id]
+	if !ok || history.pid != pid {
+		history = newMetricsHistory(pid)
+		sr.metrics[id] = history
+	}
+
+	// the first sample of a process averages the cpu over its whole life
+	cpuTime, elapsed := stat.CPUTime, stat.Uptime
+	if last := history.latest(); last != nil {
+		cpuTime, elapsed = stat.CPUTime-history.cpuTime, now.Sub(last.SampledAt)
+	}
+
+	var cpuPercent float64
+	if elapsed > 0 && cpuTime > 0 {
+		cpuPercent = float64(cpuTime) / float64(elapsed) * 100
+	}
+
+	history.cpuTime = stat.CPUTime
+	history.add(model.ProcessMetrics{
+		SampledAt:     now,
+		CPUPercent:    cpuPercent,
+		RSSBytes:      stat.RSSBytes,
+		Threads:       stat.Threads,
+		OpenFDs:       stat.OpenFDs,
+		UptimeSeconds: int64(stat.Uptime / time.Second),
+	})
+
+	return nil
+}
+
+// runMetricsSampler samples the process of every active server, histories of deleted servers are dropped
+func (sr *serverResource) runMetricsSampler() {
+	ticker := time.NewTicker(metricsSampleInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		servers := sr.getAllServer()
+		for id, srv := range servers {
+			if !srv.Status.IsActive() || srv.Pid == 0 {
+				continue
+			}
+
+			if err := sr.sampleServer(id, srv.Pid); err != nil && pkg.IsProcessAlive(srv.Pid) {
+				fmt.Printf("fail to sample server %v: %v\n", id, err)
+			}
+		}
+
+		sr.metricsMu.Lock()
+		for id := range sr.metrics {
+			if _, ok := servers[id]; !ok {
+				delete(sr.metrics, id)
+			}
+		}
+		sr.metricsMu.Unlock()
+	}
+}
+
+func (sr *serverResource) GetServerMetricsResource(id string) (model.GetServerMetricsResponse, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.GetServerMetricsResponse{}, err
+	}
+
+	sr.metricsMu.Lock()
+	defer sr.metricsMu.Unlock()
+
+	res := model.GetServerMetricsResponse{
+		History:         []model.ProcessMetrics{},
+		IntervalSeconds: int(metricsSampleInterval / time.Second),
+	}
+
+	history, ok := sr.metrics[id]
+	if !ok {
+		return res, nil
+	}
+
+	res.History = history.list()
+	if srv.Status.IsActive() && srv.Pid == history.pid {
+		res.Current = history.latest()
+	}
+
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg/slp"
+)
+
+const pingTimeout = time.Second * 2
+
+func (sr *serverResource) PingServerResource(id string) (*slp.Status, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if srv.Status != model.ServerStatusRunning {
+		return nil, errors.New("server is not running")
+	}
+
+	return slp.Ping(fmt.Sprintf("localhost:%v", srv.Port), pingTimeout)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+)
+
+// getUsedPorts returns the ports reserved by every server but except mapped to the server, the caller holds sr.mu
+func (sr *serverResource) getUsedPorts(except string) map[int]string {
+	res := make(map[int]string)
+	for id, srv := range sr.serverdata {
+		if id == except {
+			continue
+		}
+		for _, port := range srv.Config.GetReservedPorts() {
+			res[port] = id
+		}
+	}
+
+	return res
+}
+
+// checkPortsFree fails when another server reserves one of the ports of the config, the caller holds sr.mu
+func (sr *serverResource) checkPortsFree(config model.ServerConfig, except string) error {
+	used := sr.getUsedPorts(except)
+	for _, port := range config.GetReservedPorts() {
+		if id, ok := used[port]; ok {
+			return fmt.Errorf("port %v is used by server %v", port, id)
+		}
+	}
+
+	return nil
+}
+
+// allocatePort returns the lowest server port whose game, rcon and query ports are neither reserved by another
+// server nor bound on the host, the scan runs without sr.mu so the caller checks the port again while reserving it
+func (sr *serverResource) allocatePort(config model.ServerConfig, except string) (int, error) {
+	sr.mu.Lock()
+	used := sr.getUsedPorts(except)
+	sr.mu.Unlock()
+
+	for port := model.MIN_SERVER_PORT; port <= model.MAX_SERVER_PORT; port++ {
+		config.Port = port
+
+		isFree := true
+		for _, p := range config.GetReservedPorts() {
+			if _, ok := used[p]; ok {
+				isFree = false
+				break
+			}
+		}
+
+		if isFree && probeServerPorts(config) == nil {
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free port left between %v and %v", model.MIN_SERVER_PORT, model.MAX_SERVER_PORT)
+}
+
+// findFreePort allocates a port for a server that is not registered yet, its registration checks the port again
+func (sr *serverResource) findFreePort(config model.ServerConfig) (int, error) {
+	return sr.allocatePort(config, "")
+}
+
+// probeServerPorts binds the ports of the config for a moment, so a port held by a process outside the api is
+// reported before launch instead of the server failing to bind it
+func probeServerPorts(config model.ServerConfig) error {
+	if config.Port == 0 {
+		return errors.New("port is required")
+	}
+
+	for _, port := range []int{config.Port, config.GetRconPort()} {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+		if err != nil {
+			return fmt.Errorf("port %v is already in use on the host", port)
+		}
+		l.Close()
+	}
+
+	conn, err := net.ListenPacket("udp", fmt.Sprintf(":%v", config.GetQueryPort()))
+	if err != nil {
+		return fmt.Errorf("udp port %v is already in use on the host", config.GetQueryPort())
+	}
+	conn.Close()
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+func writeServerPid(id string, pid int) error {
+	return ioutil.WriteFile(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PID), []byte(strconv.Itoa(pid)), 0644)
+}
+
+func readServerPid(id string) (int, error) {
+	data, err := ioutil.ReadFile(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PID))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// isServerProcess makes sure the pid was not reused by an unrelated process since it was recorded
+func isServerProcess(id string, pid int) bool {
+	if !pkg.IsProcessAlive(pid) {
+		return false
+	}
+
+	serverDir, err := filepath.Abs(path.Join(model.DIR_SERVER, id))
+	if err != nil {
+		return false
+	}
+
+	cwd, err := pkg.GetProcessCwd(pid)
+	if err != nil || cwd != serverDir {
+		return false
+	}
+
+	cmdline, err := pkg.GetProcessCmdline(pid)
+	if err != nil {
+		return false
+	}
+	for _, arg := range cmdline {
+		if arg == "-jar" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recoverServer looks for a process left running by a previous run of the api and applies the orphan policy to it
+func (sr *serverResource) recoverServer(id string) {
+	pid, err := readServerPid(id)
+	if err != nil {
+		return
+	}
+
+	if !isServerProcess(id, pid) {
+		os.Remove(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PID))
+		return
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return
+	}
+
+	srv := sr.serverdata[id]
+	srv.Status = model.ServerStatusRunning
+	srv.Port = srv.Config.Port
+	srv.RamGB = srv.Config.RamGB
+	srv.Pid = pid
+	srv.Process = process
+	_, srv.LaunchedXmxMB = srv.Config.GetHeapMB()
+	srv.IsAdopted = true
+	srv.Exited = make(chan struct{})
+	if address, password, err := readServerRcon(id); err == nil {
+		srv.RconAddress = address
+		srv.RconPassword = password
+	}
+	if address, err := readServerQuery(id); err == nil {
+		srv.QueryAddress = address
+	}
+
+	go sr.superviseServer(id, process, srv.Exited, waitAdopted(pid))
+	go tailServerOutput(id, srv.Console, srv.Exited)
+
+	if sr.opt.OrphanPolicy == model.OrphanPolicyKill {
+		fmt.Printf("stopping orphaned server %v (pid %v)\n", id, pid)
+		srv.Status = model.ServerStatusStopping
+		go sr.runStopSequence(id, *srv, srv.Config.StopPolicy)
+		return
+	}
+
+	fmt.Printf("re-adopted server %v (pid %v)\n", id, pid)
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+)
+
+// configureServerProperties enables rcon and query in server.properties before launch, keeping an existing rcon
+// password, and returns that password
+func configureServerProperties(id string, config model.ServerConfig) (string, error) {
+	filePath := path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES)
+	props, err := properties.Load(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	password, _ := props.Get("rcon.password")
+	if password == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		password = hex.EncodeToString(buf)
+	}
+
+	props.Set("enable-rcon", "true")
+	props.Set("rcon.port", strconv.Itoa(config.GetRconPort()))
+	props.Set("rcon.password", password)
+
+	props.Set("enable-query", "true")
+	props.Set("query.port", strconv.Itoa(config.GetQueryPort()))
+
+	return password, props.Save(filePath)
+}
+
+func (sr *serverResource) GetServerPropertiesResource(id string) (model.ServerPropertiesResponse, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.ServerPropertiesResponse{}, err
+	}
+
+	props, err := properties.Load(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES))
+	if err != nil {
+		return model.ServerPropertiesResponse{}, err
+	}
+
+	return model.ServerPropertiesResponse{
+		Properties:        redactServerProperties(props.Map()),
+		IsRestartRequired: srv.IsRestartRequired,
+	}, nil
+}
+
+// UpdateServerPropertiesResource sets the given properties, a nil value removes the property so the server falls back
+// to its default, comments and ordering of the file are kept
+func (sr *serverResource) UpdateServerPropertiesResource(id string, req model.UpdateServerPropertiesRequest) (model.ServerPropertiesResponse, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.ServerPropertiesResponse{}, err
+	}
+	// inspecting the jar may take a while, keep it out of the registry lock
+	version := sr.getServerVersion(srv.Config)
+
+	var res model.ServerPropertiesResponse
+	err = sr.updateServer(id, func(s *model.Server) error {
+		for key, value := range req {
+			if value == nil {
+				if schema, ok := model.SERVER_PROPERTIES_SCHEMA[key]; ok && schema.IsManaged {
+					return fmt.Errorf("property %v is managed by the api", key)
+				}
+				continue
+			}
+			if err := model.ValidateProperty(version, key, *value); err != nil {
+				return err
+			}
+		}
+
+		filePath := path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES)
+		props, err := properties.Load(filePath)
+		if err != nil {
+			return err
+		}
+
+		isChanged := false
+		for key, value := range req {
+			current, ok := props.Get(key)
+			switch {
+			case value == nil && ok:
+				props.Delete(key)
+				isChanged = true
+			case value != nil && (!ok || current != *value):
+				props.Set(key, *value)
+				isChanged = true
+			}
+		}
+
+		if isChanged {
+			if err := props.Save(filePath); err != nil {
+				return err
+			}
+
+			// the server only reads its properties on launch
+			if s.Status.IsActive() {
+				s.IsRestartRequired = true
+			}
+		}
+
+		res = model.ServerPropertiesResponse{
+			Properties:        redactServerProperties(props.Map()),
+			IsRestartRequired: s.IsRestartRequired,
+		}
+		return nil
+	})
+
+	return res, err
+}
+
+// redactServerProperties hides the rcon password, rcon gives full control over the server
+func redactServerProperties(m map[string]string) map[string]string {
+	if _, ok := m["rcon.password"]; ok {
+		m["rcon.password"] = "********"
+	}
+
+	return m
+}
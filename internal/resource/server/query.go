@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+	"github.com/Bearaujus/minecraft-server-api/pkg/query"
+)
+
+const queryTimeout = time.Second * 2
+
+// readServerQuery returns the query address of a server from its server.properties
+func readServerQuery(id string) (string, error) {
+	props, err := properties.Load(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES))
+	if err != nil {
+		return "", err
+	}
+
+	if v, _ := props.Get("enable-query"); v != "true" {
+		return "", errors.New("query is not enabled")
+	}
+
+	port, _ := props.Get("query.port")
+	if port == "" {
+		return "", errors.New("query is not configured")
+	}
+
+	return fmt.Sprintf("localhost:%v", port), nil
+}
+
+func (sr *serverResource) QueryServerResource(id string) (*query.FullStat, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if srv.Status != model.ServerStatusRunning {
+		return nil, errors.New("server is not running")
+	}
+
+	if srv.QueryAddress == "" {
+		return nil, errors.New("query is not available")
+	}
+
+	return query.Query(srv.QueryAddress, queryTimeout)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+	"github.com/Bearaujus/minecraft-server-api/pkg/rcon"
+)
+
+const rconTimeout = time.Second * 5
+
+// readServerRcon returns the rcon address and password of a server from its server.properties
+func readServerRcon(id string) (string, string, error) {
+	props, err := properties.Load(path.Join(model.DIR_SERVER, id, model.FILE_SERVER_PROPERTIES))
+	if err != nil {
+		return "", "", err
+	}
+
+	if v, _ := props.Get("enable-rcon"); v != "true" {
+		return "", "", errors.New("rcon is not enabled")
+	}
+
+	port, _ := props.Get("rcon.port")
+	password, _ := props.Get("rcon.password")
+	if port == "" || password == "" {
+		return "", "", errors.New("rcon is not configured")
+	}
+
+	return fmt.Sprintf("localhost:%v", port), password, nil
+}
+
+// executeRcon runs the command over the cached rcon connection, reconnecting once when it went stale
+func (sr *serverResource) executeRcon(id string, command string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		client, err := sr.getRconClient(id)
+		if err != nil {
+			return "", err
+		}
+
+		res, err := client.Execute(command)
+		if err == nil {
+			return res, nil
+		}
+
+		sr.updateServer(id, func(s *model.Server) error {
+			if s.Rcon == client {
+				s.Rcon = nil
+			}
+			return nil
+		})
+		client.Close()
+
+		if attempt > 0 {
+			return "", err
+		}
+	}
+}
+
+func (sr *serverResource) getRconClient(id string) (*rcon.Client, error) {
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if srv.RconAddress == "" {
+		return nil, errors.New("rcon is not available")
+	}
+
+	if srv.Rcon != nil {
+		return srv.Rcon, nil
+	}
+
+	// dial outside of the registry lock, the first connection stored wins
+	client, err := rcon.Dial(srv.RconAddress, srv.RconPassword, rconTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *rcon.Client
+	err = sr.updateServer(id, func(s *model.Server) error {
+		if s.RconAddress != srv.RconAddress {
+			return errors.New("rcon is not available")
+		}
+
+		if s.Rcon == nil {
+			s.Rcon = client
+		}
+		res = s.Rcon
+
+		return nil
+	})
+	if err != nil || res != client {
+		client.Close()
+	}
+
+	return res, err
+}
+
+// writeServerCommand delivers a command without waiting for its reply, through stdin when the api owns the
+// process and through rcon for re-adopted servers
+func (sr *serverResource) writeServerCommand(id string, srv model.Server, command string) error {
+	if !srv.IsAdopted {
+		if _, err := fmt.Fprintln(*srv.StdinPipe, command); err != nil {
+			return err
+		}
+		fmt.Fprintln(srv.FileOut, command)
+
+		return nil
+	}
+
+	_, err := sr.executeRcon(id, command)
+	return err
+}
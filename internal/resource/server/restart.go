@@ -0,0 +1,66 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+)
+
+// RestartServerResource stops the server if needed and starts it again with its stored config
+func (sr *serverResource) RestartServerResource(id string) error {
+	var srv model.Server
+	var isRunning bool
+	err := sr.updateServer(id, func(s *model.Server) error {
+		if s.IsRestarting {
+			return errors.New("server already attempted to restart")
+		}
+
+		if err := s.Config.Validate(); err != nil {
+			return err
+		}
+
+		switch s.Status {
+		case model.ServerStatusStopping:
+			return errors.New("server is stopping")
+		case model.ServerStatusStarting, model.ServerStatusRunning:
+			if s.Process == nil {
+				return errors.New("server is starting")
+			}
+
+			if err := s.SetStatus(model.ServerStatusStopping); err != nil {
+				return err
+			}
+			s.IsRestarting = true
+			srv = *s
+			isRunning = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !isRunning {
+		return sr.StartServerResource(id, 0, 0, "")
+	}
+
+	go func() {
+		sr.runStopSequence(id, srv, srv.Config.StopPolicy)
+
+		if err := sr.StartServerResource(id, 0, 0, ""); err != nil {
+			sr.setServerRestarting(id, false)
+			fmt.Printf("fail to restart server %v: %v\n", id, err)
+		}
+	}()
+
+	return nil
+}
+
+func (sr *serverResource) setServerRestarting(id string, isRestarting bool) error {
+	return sr.updateServer(id, func(s *model.Server) error {
+		s.IsRestarting = isRestarting
+		return nil
+	})
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/archive"
+)
+
+// RestoreServerBackupResource puts the worlds of the backup back in place, a running server is only stopped when
+// isStopping is set, the replaced worlds are backed up first so the restore can be undone
+func (sr *serverResource) RestoreServerBackupResource(id, backupID string, isStopping bool) (model.RestoreBackupResponse, error) {
+	var res model.RestoreBackupResponse
+
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return res, err
+	}
+
+	backup, err := readBackup(id, backupID)
+	if err != nil {
+		return res, err
+	}
+	res.Backup = backup
+
+	// keep the server from starting while its worlds are swapped, claimed before the archive is read so nothing
+	// changes between the checksum and the restore
+	err = sr.updateServer(id, func(s *model.Server) error {
+		if s.IsRestoring {
+			return errors.New("restore already in progress")
+		}
+		if s.IsBackingUp {
+			return errors.New("server is backing up")
+		}
+		if s.Status.IsActive() && !isStopping {
+			return errors.New("server is running, stop it before restoring or pass stop=true")
+		}
+
+		s.IsRestoring = true
+		s.NextRestartAt = time.Time{}
+		srv = *s
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+	defer sr.updateServer(id, func(s *model.Server) error {
+		s.IsRestoring = false
+		return nil
+	})
+
+	// verify the archive before the server is stopped, the extraction checks the bytes it reads once more
+	sum, err := pkg.GetFileSHA256(getBackupArchivePath(id, backupID))
+	if err != nil {
+		return res, err
+	}
+	if sum != backup.SHA256 {
+		return res, fmt.Errorf("backup %v is corrupted, checksum mismatch", backupID)
+	}
+
+	if srv.Status.IsActive() {
+		if _, err := sr.StopServerResource(id, model.StopServerRequest{Wait: true}); err != nil {
+			return res, err
+		}
+		if srv, err = sr.getServer(id); err != nil {
+			return res, err
+		}
+		if srv.Status.IsActive() {
+			return res, errors.New("server is running, stop it before restoring")
+		}
+	}
+
+	serverDir := path.Join(model.DIR_SERVER, id)
+	stageDir, err := ioutil.TempDir(serverDir, ".restore-*")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(stageDir)
+
+	contentDir := path.Join(stageDir, "content")
+	if err := extractBackup(id, backup, contentDir); err != nil {
+		return res, err
+	}
+	for _, world := range backup.Worlds {
+		if err := model.ValidateWorldName(world); err != nil {
+			return res, err
+		}
+		if !pkg.IsFileOrFolderExist(path.Join(contentDir, world, "level.dat")) {
+			return res, fmt.Errorf("backup %v is missing world %v", backupID, world)
+		}
+	}
+
+	// snapshot the worlds about to be replaced, both the ones of the backup and any created since
+	replaced := getServerWorlds(id, srv.Config)
+	for _, world := range backup.Worlds {
+		if !containsString(replaced, world) && pkg.IsFileOrFolderExist(path.Join(serverDir, world)) {
+			replaced = append(replaced, world)
+		}
+	}
+	if len(replaced) > 0 {
+		safety, err := sr.createBackup(id, model.BackupTriggerPreRestore)
+		if err != nil {
+			return res, fmt.Errorf("fail to snapshot the current worlds: %v", err)
+		}
+		res.SafetyBackupID = safety.ID
+	}
+
+	if err := swapServerWorlds(serverDir, stageDir, replaced, backup.Worlds); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// swapServerWorlds moves the replaced worlds into the stage folder and the restored ones from the stage folder into
+// the server folder, everything moved is moved back when a rename fails
+func swapServerWorlds(serverDir, stageDir string, replaced, restored []string) error {
+	type move struct {
+		from string
+		to   string
+	}
+	var done []move
+
+	rename := func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			for i := len(done) - 1; i >= 0; i-- {
+				if err := os.Rename(done[i].to, done[i].from); err != nil {
+					fmt.Printf("fail to roll back %v: %v\n", done[i].from, err)
+				}
+			}
+			return err
+		}
+		done = append(done, move{from: from, to: to})
+		return nil
+	}
+
+	oldDir := path.Join(stageDir, "old")
+	if err := os.MkdirAll(oldDir, 0755); err != nil {
+		return err
+	}
+
+	for _, world := range replaced {
+		if err := rename(path.Join(serverDir, world), path.Join(oldDir, world)); err != nil {
+			return err
+		}
+	}
+
+	for _, world := range restored {
+		if err := rename(path.Join(stageDir, "content", world), path.Join(serverDir, world)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractBackup unpacks the backup archive into dst, the checksum is computed over the very bytes extracted so a file
+// replaced or corrupted meanwhile is never restored
+func extractBackup(id string, backup model.Backup, dst string) error {
+	f, err := os.Open(getBackupArchivePath(id, backup.ID))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	r := io.TeeReader(f, h)
+	extractErr := archive.ExtractTarGz(r, dst, model.MAX_WORLD_EXTRACTED_SIZE)
+
+	// the trailing bytes the extraction left unread are part of the checksum as well
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != backup.SHA256 {
+		return fmt.Errorf("backup %v is corrupted, checksum mismatch", backup.ID)
+	}
+
+	return extractErr
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg/java"
+)
+
+func (sr *serverResource) GetAllRuntimeResource() ([]java.Runtime, error) {
+	return java.Discover(sr.opt.JavaDirs), nil
+}
+
+func (sr *serverResource) findRuntime(id string) (java.Runtime, error) {
+	for _, rt := range java.Discover(sr.opt.JavaDirs) {
+		if rt.ID == id {
+			return rt, nil
+		}
+	}
+
+	return java.Runtime{}, fmt.Errorf("runtime %v not found", id)
+}
+
+// resolveRuntime picks the java of the server, launch.java_path first, then the pinned runtime and at last the java on
+// PATH, whose version is left unknown when it cannot be probed
+func (sr *serverResource) resolveRuntime(config model.ServerConfig) (java.Runtime, error) {
+	if config.Launch.JavaPath != "" {
+		rt, err := java.Probe(config.Launch.JavaPath)
+		if err != nil {
+			return rt, fmt.Errorf("java_path %v: %v", config.Launch.JavaPath, err)
+		}
+		return rt, nil
+	}
+
+	if config.Runtime != "" {
+		return sr.findRuntime(config.Runtime)
+	}
+
+	javaPath, err := exec.LookPath("java")
+	if err != nil {
+		return java.Runtime{}, errors.New("no java found on PATH, pin a runtime or set launch.java_path")
+	}
+	if abs, err := filepath.Abs(javaPath); err == nil {
+		javaPath = abs
+	}
+
+	rt, err := java.Probe(javaPath)
+	if err != nil {
+		return java.Runtime{ID: "path", Path: javaPath}, nil
+	}
+	rt.ID = "path"
+
+	return rt, nil
+}
+
+// getRequiredJavaVersion is the java major version the jar declares, or the one its minecraft version needs
+func (sr *serverResource) getRequiredJavaVersion(config model.ServerConfig) int {
+	if jar, err := sr.readJar(config.Jar); err == nil && jar.JavaVersion != 0 {
+		return jar.JavaVersion
+	}
+
+	return model.RequiredJavaVersion(sr.getServerVersion(config))
+}
+
+// checkRuntime fails when the runtime is older than the jar needs, runtimes of unknown version are let through
+func (sr *serverResource) checkRuntime(config model.ServerConfig, rt java.Runtime) error {
+	required := sr.getRequiredJavaVersion(config)
+	if required == 0 || rt.MajorVersion == 0 || rt.MajorVersion >= required {
+		return nil
+	}
+
+	return fmt.Errorf("jar %v requires java %v but runtime %v is java %v", config.Jar, required, rt.ID, rt.MajorVersion)
+}
@@ -1,7 +1,6 @@
 package server
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -10,48 +9,90 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Bearaujus/minecraft-server-api/internal/model"
 	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/slp"
 
 	"github.com/google/uuid"
 )
 
-func (sr *serverResource) GetAllServerResource() (map[string]*model.Server, error) {
-	return sr.serverdata, nil
+// GetAllServerResource returns the servers whose labels match the selector, an empty selector matches every server
+func (sr *serverResource) GetAllServerResource(selector model.LabelSelector) (map[string]model.Server, error) {
+	res := sr.getAllServer()
+	for id, srv := range res {
+		if !selector.Matches(srv.Config.Labels) {
+			delete(res, id)
+		}
+	}
+
+	return res, nil
 }
 
-func (sr *serverResource) CreateServerResource() (string, error) {
+// CreateServerResource makes an empty server, or a ready one out of the template when set
+func (sr *serverResource) CreateServerResource(template string) (string, error) {
+	if template != "" {
+		return sr.createServerFromTemplate(template)
+	}
+
 	var resID = uuid.New().String()
 	if err := os.MkdirAll(path.Join(model.DIR_SERVER, resID), os.ModePerm); err != nil {
 		return "", err
 	}
 
-	if err := sr.addServer(resID); err != nil {
+	var config = model.NewDefaultServerConfig()
+	if err := writeServerConfig(resID, config); err != nil {
+		return "", err
+	}
+
+	if err := sr.addServer(resID, config); err != nil {
 		return "", err
 	}
 
 	return resID, nil
 }
 
+// DeleteServerResource unregisters the server and moves its folder aside under the registry lock, the folder is
+// removed once the lock is released so a large server does not hold up every other request
 func (sr *serverResource) DeleteServerResource(id string) error {
-	srv, err := sr.getServer(id)
+	trashDir, err := sr.unregisterServer(id)
 	if err != nil {
 		return err
 	}
 
-	if srv != nil {
-		return errors.New("server is running")
+	return pkg.DeleteDir(trashDir)
+}
+
+func (sr *serverResource) unregisterServer(id string) (string, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	srv, ok := sr.serverdata[id]
+	if !ok {
+		return "", errors.New("server not exist")
 	}
 
-	if err := pkg.DeleteDir(path.Join(model.DIR_SERVER, id)); err != nil {
-		return err
+	if srv.Status.IsActive() {
+		return "", errors.New("server is running")
+	}
+	if srv.IsBackingUp {
+		return "", errors.New("server is backing up")
+	}
+	if srv.IsRestoring {
+		return "", errors.New("server is restoring a backup")
 	}
 
+	trashDir := path.Join(model.DIR_SERVER, serverTrashPrefix+id)
+	if err := os.Rename(path.Join(model.DIR_SERVER, id), trashDir); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	srv.Console.Close()
 	delete(sr.serverdata, id)
 
-	return nil
+	return trashDir, nil
 }
 
 func (sr *serverResource) AgreeEulaServerResource(id string) error {
@@ -89,186 +130,232 @@ func (sr *serverResource) AgreeEulaServerResource(id string) error {
 }
 
 func (sr *serverResource) StartServerResource(id string, ramGB, port int, worldName string) error {
-	srv, err := sr.getServer(id)
-	if err != nil {
-		return err
+	// a server without a port gets the lowest free one of the pool, the scan probes the host so it runs outside the
+	// registry lock and the port is checked again when it is reserved below
+	var allocated int
+	if port == 0 {
+		srv, err := sr.getServer(id)
+		if err != nil {
+			return err
+		}
+		if srv.Config.Port == 0 {
+			if allocated, err = sr.allocatePort(srv.Config, id); err != nil {
+				return err
+			}
+		}
 	}
 
-	if srv != nil {
-		return errors.New("server already started")
-	}
+	var config model.ServerConfig
+	err := sr.updateServer(id, func(s *model.Server) error {
+		switch s.Status {
+		case model.ServerStatusStarting, model.ServerStatusRunning:
+			return errors.New("server already started")
+		case model.ServerStatusStopping:
+			return errors.New("server is stopping")
+		}
+		if s.IsRestoring {
+			return errors.New("server is restoring a backup")
+		}
+		if s.IsBackingUp {
+			return errors.New("server is backing up")
+		}
 
-	if err := pkg.DeleteDir(path.Join(model.DIR_SERVER, id, "msa.std")); err != nil {
-		return err
-	}
+		// remember the given launch parameters, omitted ones fall back to the stored config
+		config = s.Config
+		if ramGB != 0 {
+			config.RamGB = ramGB
+		}
+		if port != 0 {
+			config.Port = port
+		}
+		if worldName != "" {
+			if err := model.ValidateWorldName(worldName); err != nil {
+				return err
+			}
+			config.WorldName = worldName
+		}
+		if config.Port == 0 {
+			if allocated == 0 {
+				return errors.New("server port changed while starting, try again")
+			}
+			config.Port = allocated
+		}
+		if err := sr.checkPortsFree(config, id); err != nil {
+			return err
+		}
+		if err := config.Validate(); err != nil {
+			return err
+		}
+		if !pkg.IsFileOrFolderExist(path.Join(model.DIR_JAR, config.Jar)) {
+			return fmt.Errorf("jar %v not found", config.Jar)
+		}
+		if err := sr.checkMemoryAdmission(id, config); err != nil {
+			return err
+		}
 
-	fileOut, err := os.OpenFile(path.Join(model.DIR_SERVER, id, "msa.std"), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+		if err := writeServerConfig(id, config); err != nil {
+			return err
+		}
+		s.Config = config
+		s.RestartCount = 0
+		s.NextRestartAt = time.Time{}
+
+		if err := s.SetStatus(model.ServerStatusStarting); err != nil {
+			return err
+		}
+		// count the admitted heap from now on, the launch records it again with the process
+		_, s.LaunchedXmxMB = config.GetHeapMB()
+		return nil
+	})
 	if err != nil {
 		return err
 	}
-	bufStdout := bufio.NewWriter(fileOut)
 
-	cmdArgs := []string{
-		"-XX:+UseG1GC",
-		"-XX:+ParallelRefProcEnabled",
-		"-XX:MaxGCPauseMillis=200",
-		"-XX:+UnlockExperimentalVMOptions",
-		"-XX:+DisableExplicitGC",
-		"-XX:+AlwaysPreTouch",
-		"-XX:G1NewSizePercent=30",
-		"-XX:G1MaxNewSizePercent=40",
-		"-XX:G1HeapRegionSize=8M",
-		"-XX:G1ReservePercent=20",
-		"-XX:G1HeapWastePercent=5",
-		"-XX:G1MixedGCCountTarget=4",
-		"-XX:InitiatingHeapOccupancyPercent=15",
-		"-XX:G1MixedGCLiveThresholdPercent=90",
-		"-XX:G1RSetUpdatingPauseTimePercent=5",
-		"-XX:SurvivorRatio=32",
-		"-XX:+PerfDisableSharedMem",
-		"-XX:MaxTenuringThreshold=1",
-		"-Dusing.aikars.flags=https://mcflags.emc.gs",
-		"-Daikars.new.flags=true",
+	if err := sr.launchServer(id, config); err != nil {
+		sr.setServerStatus(id, model.ServerStatusStopped)
+		return err
+	}
 
-		// set ram useage
-		fmt.Sprintf("-Xms%vG", ramGB),
-		fmt.Sprintf("-Xmx%vG", ramGB),
+	go sr.watchServerStart(id)
 
-		// set jar file
-		"-jar", "../../jar/server-1.19.2.jar",
+	return nil
+}
 
-		// set port
-		"--port", fmt.Sprint(port),
+func (sr *serverResource) launchServer(id string, config model.ServerConfig) error {
+	rt, err := sr.resolveRuntime(config)
+	if err != nil {
+		return err
+	}
+	if err := sr.checkRuntime(config, rt); err != nil {
+		return err
+	}
 
-		"--nogui",
+	if err := probeServerPorts(config); err != nil {
+		return err
 	}
 
-	if worldName != "" {
-		cmdArgs = append(cmdArgs, "--world", fmt.Sprint(worldName))
+	if err := pkg.DeleteDir(path.Join(model.DIR_SERVER, id, "msa.std")); err != nil {
+		return err
 	}
 
-	cmd := exec.Command("java", cmdArgs...)
-	cmd.Dir = path.Join(model.DIR_SERVER, id)
-	cmd.Stdout = bufStdout
-	cmd.Stderr = bufStdout
-	stdinPipe, err := cmd.StdinPipe()
+	fileOut, err := os.OpenFile(path.Join(model.DIR_SERVER, id, "msa.std"), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
 	if err != nil {
 		return err
 	}
 
-	modelServer := model.Server{
-		ID:        id,
-		Port:      port,
-		RamGB:     ramGB,
-		Cmd:       cmd,
-		StdinPipe: &stdinPipe,
-		FileOut:   fileOut,
+	rconPassword, err := configureServerProperties(id, config)
+	if err != nil {
+		fileOut.Close()
+		return err
 	}
 
-	if err := modelServer.Cmd.Start(); err != nil {
+	cmd := exec.Command(rt.Path, config.GetLaunchArgs()...)
+	cmd.Dir = path.Join(model.DIR_SERVER, id)
+	// write straight into the file and detach from our process group so the server outlives the api
+	cmd.Stdout = fileOut
+	cmd.Stderr = fileOut
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	stdinPipe, err := cmd.StdinPipe()
+	if err != nil {
+		fileOut.Close()
 		return err
 	}
 
-	if err := sr.setServer(id, &modelServer); err != nil {
+	if err := cmd.Start(); err != nil {
+		fileOut.Close()
+		return err
+	}
+	exited := make(chan struct{})
+
+	if err := writeServerPid(id, cmd.Process.Pid); err != nil {
+		fmt.Printf("fail to record pid of server %v: %v\n", id, err)
+	}
+
+	var console *pkg.Broadcaster
+	err = sr.updateServer(id, func(s *model.Server) error {
+		console = s.Console
+		s.Port = config.Port
+		s.RamGB = config.RamGB
+		s.Cmd = cmd
+		s.StdinPipe = &stdinPipe
+		s.FileOut = fileOut
+		s.Pid = cmd.Process.Pid
+		s.Process = cmd.Process
+		_, s.LaunchedXmxMB = config.GetHeapMB()
+		s.Exited = exited
+		s.RconAddress = fmt.Sprintf("localhost:%v", config.GetRconPort())
+		s.RconPassword = rconPassword
+		s.QueryAddress = fmt.Sprintf("localhost:%v", config.GetQueryPort())
+		s.IsRestartRequired = false
+		return nil
+	})
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
 		return err
 	}
 
-	// if process wasn't started properly, kill it
-	go func() error {
-		sr.serverdata[id].IsAttemptedToStart = true
-		waitTime := time.Second * 120
-		tickerTime := time.Millisecond * 500
-		ticker := time.NewTicker(tickerTime)
-
-		for range ticker.C {
-			srv, err := sr.getServer(id)
-			if err != nil {
-				return err
-			}
-
-			if srv == nil {
-				return errors.New("server is not started")
-			}
-
-			if _, err := fmt.Fprint(*srv.StdinPipe, ""); err != nil {
-				srv.Cmd.Process.Kill()
-				sr.serverdata[id] = nil
-				return errors.New("server is not started")
-			}
-
-			// stop go routine from unexpected error
-			waitTime = waitTime - tickerTime
-			if waitTime <= 0 {
-				srv.Cmd.Process.Kill()
-				sr.serverdata[id] = nil
-				return errors.New("server took too much time when starting")
-			}
-
-			// read data
-			data, err := ioutil.ReadFile(path.Join(model.DIR_SERVER, id, "msa.std"))
-			if err != nil {
-				return err
-			}
+	go sr.superviseServer(id, cmd.Process, exited, waitCmd(cmd))
+	go tailServerOutput(id, console, exited)
 
-			// success regex
-			regSuccess := regexp.MustCompile(`(?s)\[Server thread\/INFO\]: Done \((.*?)\)! For help, type "help"(?s)`)
-			if regSuccess.MatchString(string(data)) {
-				sr.serverdata[id].IsAttemptedToStart = false
-				break
-			}
+	return nil
+}
 
-			// fail regex
-			regFailToBindPort := regexp.MustCompile(`(?s)\[Server thread\/WARN\]: \*\*\*\* FAILED TO BIND TO PORT!(?s)`)
-			if regFailToBindPort.MatchString(string(data)) {
-				srv.Cmd.Process.Kill()
-				sr.serverdata[id] = nil
-				return errors.New("fail to bind port")
-			}
+// killServer kills the attached process and moves the server to the given status, the supervisor then only reaps it
+func (sr *serverResource) killServer(id string, status model.ServerStatus, reason string) error {
+	return sr.updateServer(id, func(s *model.Server) error {
+		if s.Process != nil {
+			s.Process.Kill()
 		}
 
-		return nil
-	}()
-
-	return nil
+		s.RecordExit(-1, reason)
+		return s.SetStatus(status)
+	})
 }
 
-func (sr *serverResource) StopServerResource(id string) error {
-	srv, err := sr.getServer(id)
-	if err != nil {
-		return err
-	}
+// watchServerStart marks the server running once it answers a server list ping, and kills the process if it
+// wasn't started properly
+func (sr *serverResource) watchServerStart(id string) {
+	defer sr.setServerRestarting(id, false)
 
-	if srv == nil {
-		return errors.New("server is not started")
-	}
+	waitTime := time.Second * 120
+	tickerTime := time.Millisecond * 500
+	ticker := time.NewTicker(tickerTime)
+	defer ticker.Stop()
 
-	if srv.IsAttemptedToStop {
-		return errors.New("server already attempted to stop")
-	}
+	// fail regex
+	regFailToBindPort := regexp.MustCompile(`(?s)\[Server thread\/WARN\]: \*\*\*\* FAILED TO BIND TO PORT!(?s)`)
 
-	if _, err := fmt.Fprintln(*srv.StdinPipe, "stop"); err != nil {
-		return err
-	}
+	for range ticker.C {
+		srv, err := sr.getServer(id)
+		if err != nil {
+			return
+		}
 
-	if _, err := fmt.Fprintln(srv.FileOut, "stop"); err != nil {
-		return err
-	}
+		// the server was stopped, crashed or deleted in the meantime
+		if srv.Status != model.ServerStatusStarting {
+			return
+		}
 
-	// wait for pipe until broken
-	go func() {
-		sr.serverdata[id].IsAttemptedToStop = true
-		tickerTime := time.Millisecond * 500
-		ticker := time.NewTicker(tickerTime)
-		for range ticker.C {
-			if _, err := fmt.Fprint(*srv.StdinPipe, ""); err != nil {
-				srv.Cmd.Process.Kill()
-				sr.serverdata[id] = nil
-				break
-			}
+		// stop go routine from unexpected error
+		waitTime = waitTime - tickerTime
+		if waitTime <= 0 {
+			sr.killServer(id, model.ServerStatusCrashed, "server took too much time when starting")
+			return
 		}
-	}()
 
-	return nil
+		// read data
+		data, err := ioutil.ReadFile(path.Join(model.DIR_SERVER, id, "msa.std"))
+		if err == nil && regFailToBindPort.Match(data) {
+			sr.killServer(id, model.ServerStatusCrashed, "fail to bind port")
+			return
+		}
+
+		if _, err := slp.Ping(fmt.Sprintf("localhost:%v", srv.Port), tickerTime); err == nil {
+			sr.setServerStatus(id, model.ServerStatusRunning)
+			return
+		}
+	}
 }
 
 func (sr *serverResource) GetServerConsoleResource(id string) ([]byte, error) {
@@ -277,7 +364,7 @@ func (sr *serverResource) GetServerConsoleResource(id string) ([]byte, error) {
 		return nil, err
 	}
 
-	if srv == nil {
+	if !srv.Status.IsActive() {
 		return nil, errors.New("server is not started")
 	}
 
@@ -290,27 +377,48 @@ func (sr *serverResource) GetServerConsoleResource(id string) ([]byte, error) {
 	return ioutil.ReadAll(fileOut)
 }
 
-func (sr *serverResource) AddServerConsoleResource(id, command string) error {
+// AddServerConsoleResource executes the command over rcon when the server is running and rcon answers, it then
+// returns the response text, otherwise the command goes through stdin and its output only shows in the console
+func (sr *serverResource) AddServerConsoleResource(id, command string) (string, error) {
 	srv, err := sr.getServer(id)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if srv == nil {
-		return errors.New("server is not started")
+	if !srv.Status.IsActive() || srv.Process == nil {
+		return "", errors.New("server is not started")
 	}
 
 	if command == "stop" {
-		return sr.StopServerResource(id)
+		_, err := sr.StopServerResource(id, model.StopServerRequest{})
+		return "", err
+	}
+
+	if srv.Status == model.ServerStatusRunning && srv.RconAddress != "" {
+		res, err := sr.executeRcon(id, command)
+		if err == nil {
+			if srv.FileOut != nil {
+				fmt.Fprintln(srv.FileOut, command)
+			}
+			return res, nil
+		}
+
+		if srv.IsAdopted {
+			return "", err
+		}
+	}
+
+	if srv.IsAdopted {
+		return "", errors.New("server was re-adopted and rcon is unavailable")
 	}
 
 	if _, err := fmt.Fprintln(*srv.StdinPipe, command); err != nil {
-		return err
+		return "", err
 	}
 
 	if _, err := fmt.Fprintln(srv.FileOut, command); err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return "", nil
 }
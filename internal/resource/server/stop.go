@@ -0,0 +1,127 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+)
+
+// StopServerResource starts the stop sequence, the outcome is only known when req.Wait is set
+func (sr *serverResource) StopServerResource(id string, req model.StopServerRequest) (model.StopOutcome, error) {
+	var srv model.Server
+	var policy model.StopPolicy
+	var isCanceled bool
+	err := sr.updateServer(id, func(s *model.Server) error {
+		if s.Status == model.ServerStatusStopping {
+			return errors.New("server already attempted to stop")
+		}
+
+		// cancel the restart the supervisor has scheduled
+		if s.Status == model.ServerStatusCrashed && !s.NextRestartAt.IsZero() {
+			s.NextRestartAt = time.Time{}
+			isCanceled = true
+			return nil
+		}
+
+		if !s.Status.IsActive() || s.Process == nil {
+			return errors.New("server is not started")
+		}
+
+		var err error
+		policy, err = req.Apply(s.Config.StopPolicy)
+		if err != nil {
+			return err
+		}
+
+		if err := s.SetStatus(model.ServerStatusStopping); err != nil {
+			return err
+		}
+		srv = *s
+
+		return nil
+	})
+	if err != nil || isCanceled {
+		return "", err
+	}
+
+	if !req.Wait {
+		go sr.runStopSequence(id, srv, policy)
+		return "", nil
+	}
+
+	return sr.runStopSequence(id, srv, policy), nil
+}
+
+// runStopSequence asks the server to stop, then escalates to sigterm and sigkill when it does not exit in time
+func (sr *serverResource) runStopSequence(id string, srv model.Server, policy model.StopPolicy) model.StopOutcome {
+	outcome := sr.stopProcess(id, srv, policy)
+
+	sr.updateServer(id, func(s *model.Server) error {
+		s.LastStopOutcome = outcome
+		return nil
+	})
+
+	return outcome
+}
+
+func (sr *serverResource) stopProcess(id string, srv model.Server, policy model.StopPolicy) model.StopOutcome {
+	// re-adopted servers without rcon have no console, the jvm shutdown hook still saves the world on sigterm
+	if !srv.IsAdopted || srv.RconAddress != "" {
+		if policy.WarnSeconds > 0 {
+			if sr.warnServerStop(id, srv, policy.WarnSeconds) {
+				return model.StopOutcomeStopped
+			}
+		}
+	}
+
+	if err := sr.writeServerCommand(id, srv, "stop"); err == nil {
+		select {
+		case <-srv.Exited:
+			return model.StopOutcomeStopped
+		case <-time.After(time.Duration(policy.GraceSeconds) * time.Second):
+		}
+	}
+
+	if err := srv.Process.Signal(syscall.SIGTERM); err == nil {
+		select {
+		case <-srv.Exited:
+			return model.StopOutcomeTerminated
+		case <-time.After(time.Duration(policy.TermSeconds) * time.Second):
+		}
+	}
+
+	srv.Process.Kill()
+	<-srv.Exited
+
+	return model.StopOutcomeKilled
+}
+
+// warnServerStop announces the countdown in game, it reports whether the server exited in the meantime
+func (sr *serverResource) warnServerStop(id string, srv model.Server, seconds int) bool {
+	marks := []int{seconds}
+	for _, v := range []int{60, 30, 10, 5, 4, 3, 2, 1} {
+		if v < seconds {
+			marks = append(marks, v)
+		}
+	}
+
+	for i, v := range marks {
+		sr.writeServerCommand(id, srv, fmt.Sprintf("say Server stopping in %v seconds", v))
+
+		next := 0
+		if i+1 < len(marks) {
+			next = marks[i+1]
+		}
+
+		select {
+		case <-srv.Exited:
+			return true
+		case <-time.After(time.Duration(v-next) * time.Second):
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+)
+
+// backupRestartDelay is how long a crashed server waits for a running backup before its restart is tried again
+const backupRestartDelay = time.Second * 5
+
+// waitCmd waits for a process spawned by the api and describes how it exited
+func waitCmd(cmd *exec.Cmd) func() (int, string) {
+	return func() (int, string) {
+		cmd.Wait()
+		return cmd.ProcessState.ExitCode(), cmd.ProcessState.String()
+	}
+}
+
+// waitAdopted polls a re-adopted process, its exit status belongs to its real parent so it is unknown to us
+func waitAdopted(pid int) func() (int, string) {
+	return func() (int, string) {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !pkg.IsProcessAlive(pid) {
+				break
+			}
+		}
+
+		return -1, "process exited"
+	}
+}
+
+// superviseServer waits until the process exits, records why, and applies the restart policy of the server
+func (sr *serverResource) superviseServer(id string, process *os.Process, exited chan struct{}, wait func() (int, string)) {
+	exitCode, reason := wait()
+	defer close(exited)
+
+	var restart bool
+	var backoff time.Duration
+	err := sr.updateServer(id, func(s *model.Server) error {
+		// the process was already detached, e.g. killed after a failed start
+		if s.Process != process {
+			return errors.New("server process was replaced")
+		}
+
+		s.RecordExit(exitCode, reason)
+
+		if s.Status == model.ServerStatusStopping {
+			return s.SetStatus(model.ServerStatusStopped)
+		}
+
+		// a server waiting for its restart stays crashed even after a clean exit
+		willRestart := s.Config.RestartPolicy.ShouldRestart(exitCode, s.RestartCount)
+		next := model.ServerStatusCrashed
+		if exitCode == 0 && !willRestart {
+			next = model.ServerStatusStopped
+		}
+		if err := s.SetStatus(next); err != nil {
+			return err
+		}
+
+		if willRestart {
+			restart = true
+			backoff = s.Config.RestartPolicy.Backoff(s.RestartCount)
+			s.NextRestartAt = time.Now().Add(backoff)
+		}
+
+		return nil
+	})
+	if err != nil || !restart {
+		return
+	}
+
+	fmt.Printf("server %v exited (%v), restarting in %v\n", id, reason, backoff)
+	sr.scheduleRestart(id, backoff)
+}
+
+func (sr *serverResource) scheduleRestart(id string, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if err := sr.restartCrashedServer(id); err != nil {
+			fmt.Printf("fail to restart server %v: %v\n", id, err)
+		}
+	})
+}
+
+func (sr *serverResource) restartCrashedServer(id string) error {
+	var config model.ServerConfig
+	var isDeferred bool
+	err := sr.updateServer(id, func(s *model.Server) error {
+		// the server was started, deleted or otherwise handled while waiting
+		if s.Status != model.ServerStatusCrashed || s.NextRestartAt.IsZero() {
+			return errors.New("server is no longer waiting for a restart")
+		}
+
+		// the worlds are being archived, keep waiting until the backup is done
+		if s.IsBackingUp {
+			s.NextRestartAt = time.Now().Add(backupRestartDelay)
+			isDeferred = true
+			return nil
+		}
+
+		// other servers may have taken the memory while this one was down, give up restarting then
+		if err := sr.checkMemoryAdmission(id, s.Config); err != nil {
+			s.NextRestartAt = time.Time{}
+			s.RecordExit(-1, err.Error())
+			return err
+		}
+
+		config = s.Config
+		s.RestartCount++
+		s.NextRestartAt = time.Time{}
+
+		if err := s.SetStatus(model.ServerStatusStarting); err != nil {
+			return err
+		}
+		_, s.LaunchedXmxMB = config.GetHeapMB()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if isDeferred {
+		sr.scheduleRestart(id, backupRestartDelay)
+		return nil
+	}
+
+	if err := sr.launchServer(id, config); err != nil {
+		sr.updateServer(id, func(s *model.Server) error {
+			s.RecordExit(-1, err.Error())
+			return s.SetStatus(model.ServerStatusCrashed)
+		})
+		return err
+	}
+
+	go sr.watchServerStart(id)
+
+	return nil
+}
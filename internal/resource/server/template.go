@@ -0,0 +1,317 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/archive"
+	"github.com/Bearaujus/minecraft-server-api/pkg/properties"
+
+	"github.com/google/uuid"
+)
+
+func getTemplateDir(name string) string {
+	return path.Join(model.DIR_TEMPLATE, name)
+}
+
+func readTemplate(name string) (model.Template, error) {
+	if err := model.ValidateTemplateName(name); err != nil {
+		return model.Template{}, err
+	}
+
+	templateDir := getTemplateDir(name)
+	data, err := ioutil.ReadFile(path.Join(templateDir, model.FILE_TEMPLATE_CONFIG))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.Template{}, fmt.Errorf("template %v not found", name)
+		}
+		return model.Template{}, err
+	}
+
+	var res model.Template
+	if err := json.Unmarshal(data, &res); err != nil {
+		return model.Template{}, err
+	}
+	res.Name = name
+	res = res.Normalize()
+
+	res.HasWorld = pkg.IsFileOrFolderExist(path.Join(templateDir, model.DEFAULT_WORLD_NAME, "level.dat"))
+	res.HasPlugins = pkg.IsFileOrFolderExist(path.Join(templateDir, model.DIR_TEMPLATE_PLUGINS))
+
+	return res, nil
+}
+
+func writeTemplate(t model.Template) error {
+	// what the folder holds is read from disk, do not store a copy that can go stale
+	t.HasWorld = false
+	t.HasPlugins = false
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filePath := path.Join(getTemplateDir(t.Name), model.FILE_TEMPLATE_CONFIG)
+	if err := ioutil.WriteFile(filePath+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(filePath+".tmp", filePath)
+}
+
+func (sr *serverResource) GetAllTemplateResource() ([]model.Template, error) {
+	if err := pkg.ValidateDir(true, model.DIR_TEMPLATE); err != nil {
+		return nil, err
+	}
+
+	names, err := pkg.GetListFolderFromDir(model.DIR_TEMPLATE)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	res := []model.Template{}
+	for _, name := range names {
+		if model.ValidateTemplateName(name) != nil {
+			continue
+		}
+
+		t, err := readTemplate(name)
+		if err != nil {
+			fmt.Printf("fail to read template %v: %v\n", name, err)
+			continue
+		}
+		res = append(res, t)
+	}
+
+	return res, nil
+}
+
+func (sr *serverResource) GetTemplateResource(name string) (model.Template, error) {
+	return readTemplate(name)
+}
+
+// PutTemplateResource creates the template or replaces its definition, the world and plugins it holds are kept
+func (sr *serverResource) PutTemplateResource(name string, t model.Template) (model.Template, error) {
+	t.Name = name
+	t = t.Normalize()
+
+	if err := model.ValidateTemplateName(name); err != nil {
+		return model.Template{}, err
+	}
+
+	jar, err := sr.readJar(t.Jar)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	if err := t.Validate(jar.Version); err != nil {
+		return model.Template{}, err
+	}
+
+	sr.templateMu.Lock()
+	defer sr.templateMu.Unlock()
+
+	if err := pkg.ValidateDir(true, getTemplateDir(name)); err != nil {
+		return model.Template{}, err
+	}
+
+	if err := writeTemplate(t); err != nil {
+		return model.Template{}, err
+	}
+
+	return readTemplate(name)
+}
+
+func (sr *serverResource) DeleteTemplateResource(name string) error {
+	if _, err := readTemplate(name); err != nil {
+		return err
+	}
+
+	sr.templateMu.Lock()
+	defer sr.templateMu.Unlock()
+
+	return os.RemoveAll(getTemplateDir(name))
+}
+
+// UploadTemplateWorldResource unpacks a world archive as the seed world of the template
+func (sr *serverResource) UploadTemplateWorldResource(name string, body io.Reader, req model.UploadRequest) (model.Template, error) {
+	return sr.uploadTemplateFolder(name, model.DEFAULT_WORLD_NAME, body, req, findWorldDir)
+}
+
+// UploadTemplatePluginsResource unpacks an archive of plugin jars and their configs as the plugins folder of the
+// template
+func (sr *serverResource) UploadTemplatePluginsResource(name string, body io.Reader, req model.UploadRequest) (model.Template, error) {
+	return sr.uploadTemplateFolder(name, model.DIR_TEMPLATE_PLUGINS, body, req, findPluginsDir)
+}
+
+// uploadTemplateFolder unpacks the archive into a stage inside the template folder and swaps the folder found by find
+// in as folder
+func (sr *serverResource) uploadTemplateFolder(name, folder string, body io.Reader, req model.UploadRequest, find func(string) (string, error)) (model.Template, error) {
+	if err := req.Validate(); err != nil {
+		return model.Template{}, err
+	}
+
+	if _, err := readTemplate(name); err != nil {
+		return model.Template{}, err
+	}
+
+	templateDir := getTemplateDir(name)
+	if !req.IsOverwrite && pkg.IsFileOrFolderExist(path.Join(templateDir, folder)) {
+		return model.Template{}, fmt.Errorf("template %v already has %v", name, folder)
+	}
+
+	tmpPath, _, _, err := receiveUpload(templateDir, body, model.MAX_WORLD_UPLOAD_SIZE, req)
+	if err != nil {
+		return model.Template{}, err
+	}
+	defer os.Remove(tmpPath)
+
+	stageDir, err := ioutil.TempDir(templateDir, ".upload-*")
+	if err != nil {
+		return model.Template{}, err
+	}
+	defer os.RemoveAll(stageDir)
+
+	contentDir := path.Join(stageDir, "content")
+	if err := archive.Extract(tmpPath, contentDir, model.MAX_WORLD_EXTRACTED_SIZE); err != nil {
+		return model.Template{}, err
+	}
+
+	srcDir, err := find(contentDir)
+	if err != nil {
+		return model.Template{}, err
+	}
+
+	sr.templateMu.Lock()
+	defer sr.templateMu.Unlock()
+
+	dst := path.Join(templateDir, folder)
+	if pkg.IsFileOrFolderExist(dst) {
+		if !req.IsOverwrite {
+			return model.Template{}, fmt.Errorf("template %v already has %v", name, folder)
+		}
+		// the old folder is dropped along with the stage folder
+		if err := os.Rename(dst, path.Join(stageDir, "old")); err != nil {
+			return model.Template{}, err
+		}
+	}
+
+	if err := os.Rename(srcDir, dst); err != nil {
+		return model.Template{}, err
+	}
+
+	return readTemplate(name)
+}
+
+// findPluginsDir returns the plugins folder of the archive, either dir itself or its only sub folder when that one is
+// named plugins
+func findPluginsDir(dir string) (string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", errors.New("archive is empty")
+	}
+	if len(files) == 1 && files[0].IsDir() && files[0].Name() == model.DIR_TEMPLATE_PLUGINS {
+		return path.Join(dir, files[0].Name()), nil
+	}
+
+	return dir, nil
+}
+
+// createServerFromTemplate makes a server folder out of the template, with its jar, launch settings, properties,
+// plugins and seed world, listening on a free port
+func (sr *serverResource) createServerFromTemplate(name string) (string, error) {
+	sr.templateMu.Lock()
+	defer sr.templateMu.Unlock()
+
+	t, err := readTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := sr.readJar(t.Jar); err != nil {
+		return "", err
+	}
+
+	config := t.GetServerConfig()
+	if config.Port, err = sr.findFreePort(config); err != nil {
+		return "", err
+	}
+
+	var resID = uuid.New().String()
+	serverDir := path.Join(model.DIR_SERVER, resID)
+	if err := os.MkdirAll(serverDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	if err := materializeTemplate(t, serverDir); err != nil {
+		os.RemoveAll(serverDir)
+		return "", err
+	}
+
+	if err := writeServerConfig(resID, config); err != nil {
+		os.RemoveAll(serverDir)
+		return "", err
+	}
+
+	if err := sr.addServer(resID, config); err != nil {
+		os.RemoveAll(serverDir)
+		return "", err
+	}
+
+	if t.IsEulaAgreed {
+		if err := sr.AgreeEulaServerResource(resID); err != nil {
+			sr.DeleteServerResource(resID)
+			return "", err
+		}
+	}
+
+	return resID, nil
+}
+
+// materializeTemplate copies the seed world and plugins of the template into the server folder and writes its
+// properties
+func materializeTemplate(t model.Template, serverDir string) error {
+	templateDir := getTemplateDir(t.Name)
+
+	if t.HasWorld {
+		src := path.Join(templateDir, model.DEFAULT_WORLD_NAME)
+		if err := pkg.CopyDirWithFilter(src, path.Join(serverDir, t.GetWorldName()), isCloneSkipped); err != nil {
+			return err
+		}
+	}
+
+	if t.HasPlugins {
+		if err := pkg.CopyDir(path.Join(templateDir, model.DIR_TEMPLATE_PLUGINS), path.Join(serverDir, model.DIR_TEMPLATE_PLUGINS)); err != nil {
+			return err
+		}
+	}
+
+	if len(t.Properties) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(t.Properties))
+	for key := range t.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	props := properties.New()
+	for _, key := range keys {
+		props.Set(key, t.Properties[key])
+	}
+
+	return props.Save(path.Join(serverDir, model.FILE_SERVER_PROPERTIES))
+}
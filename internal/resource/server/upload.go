@@ -0,0 +1,162 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/Bearaujus/minecraft-server-api/internal/model"
+	"github.com/Bearaujus/minecraft-server-api/pkg"
+	"github.com/Bearaujus/minecraft-server-api/pkg/archive"
+)
+
+// receiveUpload streams the body into a hidden temporary file inside dir, capping it at maxSize and checking the
+// expected checksum, the caller owns the returned file
+func receiveUpload(dir string, body io.Reader, maxSize int64, req model.UploadRequest) (string, int64, string, error) {
+	tmp, err := ioutil.TempFile(dir, ".upload-*")
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(body, maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > maxSize {
+		err = fmt.Errorf("upload cannot > %v bytes", maxSize)
+	}
+	if err == nil && size == 0 {
+		err = errors.New("upload cannot be empty")
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if err == nil && !req.IsChecksumMatch(sum) {
+		err = fmt.Errorf("sha256 mismatch, expected %v but got %v", req.SHA256, sum)
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", 0, "", err
+	}
+
+	return tmp.Name(), size, sum, nil
+}
+
+// UploadServerWorldResource unpacks a zip or tar.gz world archive into the server folder as the world, the archive
+// may hold the world files directly or inside a single top folder
+func (sr *serverResource) UploadServerWorldResource(id, worldName string, body io.Reader, req model.UploadRequest) (model.UploadWorldResponse, error) {
+	if err := req.Validate(); err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+
+	srv, err := sr.getServer(id)
+	if err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+	if srv.Status.IsActive() {
+		return model.UploadWorldResponse{}, errors.New("server is running, stop it before uploading a world")
+	}
+	if srv.IsBackingUp {
+		return model.UploadWorldResponse{}, errors.New("server is backing up")
+	}
+	if srv.IsRestoring {
+		return model.UploadWorldResponse{}, errors.New("server is restoring a backup")
+	}
+
+	if worldName == "" {
+		worldName = srv.Config.WorldName
+	}
+	if worldName == "" {
+		worldName = model.DEFAULT_WORLD_NAME
+	}
+	if err := model.ValidateWorldName(worldName); err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+
+	serverDir := path.Join(model.DIR_SERVER, id)
+	if !req.IsOverwrite && pkg.IsFileOrFolderExist(path.Join(serverDir, worldName)) {
+		return model.UploadWorldResponse{}, fmt.Errorf("world %v already exists", worldName)
+	}
+
+	tmpPath, size, sum, err := receiveUpload(serverDir, body, model.MAX_WORLD_UPLOAD_SIZE, req)
+	if err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+	defer os.Remove(tmpPath)
+
+	// stage next to the world so the final move is a rename on the same filesystem
+	stageDir, err := ioutil.TempDir(serverDir, ".world-upload-*")
+	if err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+	defer os.RemoveAll(stageDir)
+
+	contentDir := path.Join(stageDir, "content")
+	if err := archive.Extract(tmpPath, contentDir, model.MAX_WORLD_EXTRACTED_SIZE); err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+
+	worldDir, err := findWorldDir(contentDir)
+	if err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+
+	// swap the world in under the registry lock so the server cannot start halfway
+	err = sr.updateServer(id, func(s *model.Server) error {
+		if s.Status.IsActive() {
+			return errors.New("server is running, stop it before uploading a world")
+		}
+		// the world folders are being archived or swapped
+		if s.IsBackingUp {
+			return errors.New("server is backing up")
+		}
+		if s.IsRestoring {
+			return errors.New("server is restoring a backup")
+		}
+
+		dst := path.Join(serverDir, worldName)
+		if pkg.IsFileOrFolderExist(dst) {
+			if !req.IsOverwrite {
+				return fmt.Errorf("world %v already exists", worldName)
+			}
+			// the old world is dropped along with the stage folder
+			if err := os.Rename(dst, path.Join(stageDir, "old")); err != nil {
+				return err
+			}
+		}
+
+		return os.Rename(worldDir, dst)
+	})
+	if err != nil {
+		return model.UploadWorldResponse{}, err
+	}
+
+	return model.UploadWorldResponse{
+		WorldName: worldName,
+		Size:      size,
+		SHA256:    sum,
+	}, nil
+}
+
+// findWorldDir returns the folder holding level.dat, either dir itself or its only sub folder
+func findWorldDir(dir string) (string, error) {
+	if pkg.IsFileOrFolderExist(path.Join(dir, "level.dat")) {
+		return dir, nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 1 && files[0].IsDir() && pkg.IsFileOrFolderExist(path.Join(dir, files[0].Name(), "level.dat")) {
+		return path.Join(dir, files[0].Name()), nil
+	}
+
+	return "", errors.New("archive does not contain a world, level.dat is missing")
+}
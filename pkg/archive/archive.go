@@ -0,0 +1,299 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatZip   Format = "zip"
+	FormatTarGz Format = "tar.gz"
+)
+
+var (
+	magicZip  = []byte("PK\x03\x04")
+	magicGzip = []byte{0x1f, 0x8b}
+)
+
+// DetectFormat tells the archive format from the first bytes of the file
+func DetectFormat(filePath string) (Format, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 4)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, magicZip):
+		return FormatZip, nil
+	case bytes.HasPrefix(head, magicGzip):
+		return FormatTarGz, nil
+	}
+
+	return "", errors.New("archive must be a zip or tar.gz file")
+}
+
+// Extract unpacks the archive into dst, entries escaping dst and links are rejected and the unpacked size is capped
+// at maxSize so a small archive cannot fill the disk
+func Extract(filePath, dst string, maxSize int64) error {
+	format, err := DetectFormat(filePath)
+	if err != nil {
+		return err
+	}
+
+	x := &extractor{
+		dst:       dst,
+		remaining: maxSize,
+	}
+
+	if format == FormatZip {
+		return x.extractZip(filePath)
+	}
+
+	return x.extractTarGz(filePath)
+}
+
+type extractor struct {
+	dst       string
+	remaining int64
+}
+
+// target resolves the entry name inside dst, it fails on absolute names and names climbing out of dst
+func (x *extractor) target(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("archive entry %v has an absolute path", name)
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("archive entry %v escapes the target folder", name)
+	}
+
+	return filepath.Join(x.dst, filepath.FromSlash(cleaned)), nil
+}
+
+func (x *extractor) writeFile(target string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, io.LimitReader(r, x.remaining+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	x.remaining -= n
+	if x.remaining < 0 {
+		return errors.New("archive content is too large")
+	}
+
+	return nil
+}
+
+func (x *extractor) extractZip(filePath string) error {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		target, err := x.target(f.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = x.writeFile(target, mode, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("archive entry %v is not a regular file", f.Name)
+		}
+	}
+
+	return nil
+}
+
+// ExtractTarGz unpacks the tar.gz stream into dst with the same checks as Extract
+func ExtractTarGz(r io.Reader, dst string, maxSize int64) error {
+	x := &extractor{
+		dst:       dst,
+		remaining: maxSize,
+	}
+
+	return x.readTarGz(r)
+}
+
+func (x *extractor) extractTarGz(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return x.readTarGz(f)
+}
+
+func (x *extractor) readTarGz(r io.Reader) error {
+	gr, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := x.target(header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := x.writeFile(target, os.FileMode(header.Mode), tr); err != nil {
+				return err
+			}
+		case tar.TypeXGlobalHeader:
+			// pax metadata of the whole archive, nothing to extract
+		default:
+			return fmt.Errorf("archive entry %v is not a regular file", header.Name)
+		}
+	}
+}
+
+// WriteTarGz archives the named files and folders of baseDir into w, extra holds generated files written first, skip
+// tells the relative paths to leave out
+func WriteTarGz(w io.Writer, baseDir string, names []string, extra map[string][]byte, skip func(string) bool) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	extraNames := make([]string, 0, len(extra))
+	for name := range extra {
+		extraNames = append(extraNames, name)
+	}
+	sort.Strings(extraNames)
+	for _, name := range extraNames {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(extra[name])),
+			ModTime: time.Now(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(extra[name]); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		err := filepath.Walk(filepath.Join(baseDir, name), func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(baseDir, filePath)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if skip != nil && skip(rel) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !info.IsDir() && !info.Mode().IsRegular() {
+				return nil
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = rel
+			if info.IsDir() {
+				header.Name += "/"
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return nil
+			}
+
+			f, err := os.Open(filePath)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			// the file may grow while it is archived, only copy the size written in the header
+			_, err = io.CopyN(tw, f, header.Size)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
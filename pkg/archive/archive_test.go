@@ -0,0 +1,255 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testEntry is an archive entry, a non empty link makes it a symlink
+type testEntry struct {
+	name string
+	body string
+	link string
+}
+
+func writeTestZip(t *testing.T, entries []testEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		switch {
+		case e.link != "":
+			header.SetMode(os.ModeSymlink | 0777)
+		case strings.HasSuffix(e.name, "/"):
+			header.SetMode(os.ModeDir | 0755)
+		default:
+			header.SetMode(0644)
+		}
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.link != "" {
+			w.Write([]byte(e.link))
+		} else {
+			w.Write([]byte(e.body))
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return writeTestFile(t, "archive.zip", buf.Bytes())
+}
+
+func writeTestTarGz(t *testing.T, entries []testEntry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0644, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		switch {
+		case e.link != "":
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, e.link, 0
+		case strings.HasSuffix(e.name, "/"):
+			header.Typeflag, header.Mode, header.Size = tar.TypeDir, 0755, 0
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			tw.Write([]byte(e.body))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return writeTestFile(t, "archive.tar.gz", buf.Bytes())
+}
+
+func writeTestFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+var testFormats = []struct {
+	name  string
+	write func(t *testing.T, entries []testEntry) string
+}{
+	{name: "zip", write: writeTestZip},
+	{name: "tar.gz", write: writeTestTarGz},
+}
+
+func TestExtract(t *testing.T) {
+	entries := []testEntry{
+		{name: "world/"},
+		{name: "world/level.dat", body: "level"},
+		{name: "world/region/r.0.0.mca", body: "region"},
+		{name: "world_nether/./level.dat", body: "nether"},
+	}
+
+	for _, format := range testFormats {
+		t.Run(format.name, func(t *testing.T) {
+			dst := t.TempDir()
+			if err := Extract(format.write(t, entries), dst, 1024); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+
+			for name, want := range map[string]string{
+				"world/level.dat":        "level",
+				"world/region/r.0.0.mca": "region",
+				"world_nether/level.dat": "nether",
+			} {
+				got, err := os.ReadFile(filepath.Join(dst, name))
+				if err != nil {
+					t.Errorf("reading %v: %v", name, err)
+					continue
+				}
+				if string(got) != want {
+					t.Errorf("%v = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []testEntry
+		maxSize int64
+		wantErr string
+	}{
+		{
+			name:    "parent entry",
+			entries: []testEntry{{name: "../evil.txt", body: "evil"}},
+			maxSize: 1024,
+			wantErr: "escapes the target folder",
+		},
+		{
+			name:    "nested parent entry",
+			entries: []testEntry{{name: "world/../../evil.txt", body: "evil"}},
+			maxSize: 1024,
+			wantErr: "escapes the target folder",
+		},
+		{
+			name:    "backslash parent entry",
+			entries: []testEntry{{name: `..\evil.txt`, body: "evil"}},
+			maxSize: 1024,
+			wantErr: "escapes the target folder",
+		},
+		{
+			name:    "absolute entry",
+			entries: []testEntry{{name: "/tmp/evil.txt", body: "evil"}},
+			maxSize: 1024,
+			wantErr: "absolute path",
+		},
+		{
+			name:    "symlink entry",
+			entries: []testEntry{{name: "world/link", link: "/etc/passwd"}},
+			maxSize: 1024,
+			wantErr: "not a regular file",
+		},
+		{
+			name:    "over the size limit",
+			entries: []testEntry{{name: "a.txt", body: strings.Repeat("a", 600)}, {name: "b.txt", body: strings.Repeat("b", 600)}},
+			maxSize: 1000,
+			wantErr: "too large",
+		},
+	}
+
+	for _, format := range testFormats {
+		for _, tt := range tests {
+			t.Run(format.name+"/"+tt.name, func(t *testing.T) {
+				parent := t.TempDir()
+				dst := filepath.Join(parent, "dst")
+
+				err := Extract(format.write(t, tt.entries), dst, tt.maxSize)
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Extract() error = %v, want %v", err, tt.wantErr)
+				}
+
+				if _, err := os.Lstat(filepath.Join(parent, "evil.txt")); err == nil {
+					t.Error("an entry was written outside the target folder")
+				}
+			})
+		}
+	}
+}
+
+func TestExtractExactSizeLimit(t *testing.T) {
+	for _, format := range testFormats {
+		t.Run(format.name, func(t *testing.T) {
+			entries := []testEntry{{name: "a.txt", body: strings.Repeat("a", 512)}, {name: "b.txt", body: strings.Repeat("b", 512)}}
+			if err := Extract(format.write(t, entries), t.TempDir(), 1024); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if _, err := DetectFormat(writeTestFile(t, "archive.rar", []byte("Rar!\x1a\x07"))); err == nil {
+		t.Error("DetectFormat() error = nil, want an unsupported format error")
+	}
+	if _, err := DetectFormat(writeTestFile(t, "empty", nil)); err == nil {
+		t.Error("DetectFormat() of an empty file error = nil, want an error")
+	}
+}
+
+func TestWriteTarGzRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "world", "region"), 0755)
+	os.WriteFile(filepath.Join(src, "world", "level.dat"), []byte("level"), 0644)
+	os.WriteFile(filepath.Join(src, "world", "session.lock"), []byte("lock"), 0644)
+	os.WriteFile(filepath.Join(src, "world", "region", "r.0.0.mca"), []byte("region"), 0644)
+
+	var buf bytes.Buffer
+	err := WriteTarGz(&buf, src, []string{"world"}, map[string][]byte{"manifest.json": []byte("{}")}, func(rel string) bool {
+		return rel == "world/session.lock"
+	})
+	if err != nil {
+		t.Fatalf("WriteTarGz() error = %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := Extract(writeTestFile(t, "backup.tar.gz", buf.Bytes()), dst, 1024); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"manifest.json":          "{}",
+		"world/level.dat":        "level",
+		"world/region/r.0.0.mca": "region",
+	} {
+		got, err := os.ReadFile(filepath.Join(dst, name))
+		if err != nil || string(got) != want {
+			t.Errorf("%v = %q (%v), want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "world", "session.lock")); err == nil {
+		t.Error("skipped file session.lock was archived")
+	}
+}
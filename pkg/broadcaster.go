@@ -0,0 +1,112 @@
+package pkg
+
+import "sync"
+
+// Broadcaster fans every published line out to its subscribers and keeps the latest lines as backlog
+type Broadcaster struct {
+	mu          sync.Mutex
+	backlogSize int
+	backlog     []string
+	subscribers map[*Subscription]struct{}
+	isClosed    bool
+}
+
+// Subscription receives lines published after it was created, lines are dropped while it is not keeping up
+type Subscription struct {
+	Backlog []string
+	Lines   <-chan string
+
+	lines       chan string
+	broadcaster *Broadcaster
+}
+
+func NewBroadcaster(backlogSize int) *Broadcaster {
+	return &Broadcaster{
+		backlogSize: backlogSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (b *Broadcaster) Publish(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isClosed {
+		return
+	}
+
+	b.backlog = append(b.backlog, line)
+	if len(b.backlog) > b.backlogSize {
+		b.backlog = b.backlog[len(b.backlog)-b.backlogSize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.lines <- line:
+		default:
+		}
+	}
+}
+
+// Reset drops the backlog, e.g. when the output it mirrors starts over
+func (b *Broadcaster) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.backlog = nil
+}
+
+// Subscribe returns at most backlog of the latest lines along with the channel of upcoming ones
+func (b *Broadcaster) Subscribe(backlog int) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := make(chan string, 256)
+	sub := &Subscription{
+		Lines:       lines,
+		lines:       lines,
+		broadcaster: b,
+	}
+
+	if backlog > len(b.backlog) {
+		backlog = len(b.backlog)
+	}
+	if backlog > 0 {
+		sub.Backlog = append([]string{}, b.backlog[len(b.backlog)-backlog:]...)
+	}
+
+	if b.isClosed {
+		close(lines)
+		return sub
+	}
+	b.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+// Close ends every subscription, later publishes are ignored
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isClosed {
+		return
+	}
+	b.isClosed = true
+
+	for sub := range b.subscribers {
+		close(sub.lines)
+		delete(b.subscribers, sub)
+	}
+}
+
+func (s *Subscription) Close() {
+	b := s.broadcaster
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[s]; ok {
+		close(s.lines)
+		delete(b.subscribers, s)
+	}
+}
@@ -1,6 +1,8 @@
 package pkg
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -69,6 +71,16 @@ func CopyFile(src, dst string) error {
 }
 
 func CopyDir(src string, dst string) error {
+	return CopyDirWithFilter(src, dst, nil)
+}
+
+// CopyDirWithFilter copies src into dst, leaving out the paths relative to src that skip reports, and stops at the
+// first error
+func CopyDirWithFilter(src string, dst string, skip func(rel string) bool) error {
+	return copyDir(src, dst, "", skip)
+}
+
+func copyDir(src string, dst string, rel string, skip func(rel string) bool) error {
 	var err error
 	var fds []os.FileInfo
 	var srcinfo os.FileInfo
@@ -88,14 +100,19 @@ func CopyDir(src string, dst string) error {
 	for _, fd := range fds {
 		srcfp := path.Join(src, fd.Name())
 		dstfp := path.Join(dst, fd.Name())
+		relfp := path.Join(rel, fd.Name())
+
+		if skip != nil && skip(relfp) {
+			continue
+		}
 
 		if fd.IsDir() {
-			if err = CopyDir(srcfp, dstfp); err != nil {
-				fmt.Println(err)
+			if err = copyDir(srcfp, dstfp, relfp, skip); err != nil {
+				return err
 			}
 		} else {
 			if err = CopyFile(srcfp, dstfp); err != nil {
-				fmt.Println(err)
+				return err
 			}
 		}
 	}
@@ -135,3 +152,19 @@ func GetListFolderFromDir(dirpath string) ([]string, error) {
 
 	return res, nil
 }
+
+// GetFileSHA256 returns the hex encoded sha256 of the file content
+func GetFileSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,180 @@
+package java
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const probeTimeout = 5 * time.Second
+
+// DEFAULT_DIRS are the usual install locations of jdks on linux
+var DEFAULT_DIRS = []string{
+	"/usr/lib/jvm",
+	"/usr/java",
+	"/opt/java",
+	"/opt/jdk",
+}
+
+var versionOutputRegex = regexp.MustCompile(`version "([^"]+)"`)
+
+type Runtime struct {
+	// ID is the folder name of the java home, or "path" for the java found on PATH
+	ID           string `json:"id"`
+	Home         string `json:"home"`
+	Path         string `json:"path"`
+	Version      string `json:"version"`
+	MajorVersion int    `json:"major_version"`
+	Vendor       string `json:"vendor,omitempty"`
+}
+
+// Discover lists the runtimes found directly inside dirs and the one on PATH, runtimes reachable through several
+// paths are listed once
+func Discover(dirs []string) []Runtime {
+	var res []Runtime
+	seen := make(map[string]bool)
+
+	add := func(id, javaPath string) {
+		realPath, err := filepath.EvalSymlinks(javaPath)
+		if err != nil || seen[realPath] {
+			return
+		}
+
+		rt, err := Probe(javaPath)
+		if err != nil {
+			return
+		}
+		seen[realPath] = true
+
+		rt.ID = id
+		res = append(res, rt)
+	}
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, f := range files {
+			home := filepath.Join(dir, f.Name())
+			if info, err := os.Stat(home); err != nil || !info.IsDir() {
+				continue
+			}
+			add(f.Name(), filepath.Join(home, "bin", "java"))
+		}
+	}
+
+	if javaPath, err := exec.LookPath("java"); err == nil {
+		if abs, err := filepath.Abs(javaPath); err == nil {
+			add("path", abs)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].MajorVersion != res[j].MajorVersion {
+			return res[i].MajorVersion < res[j].MajorVersion
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// Probe reads the version of the java binary from the release file of its home, falling back to `java -version`
+func Probe(javaPath string) (Runtime, error) {
+	info, err := os.Stat(javaPath)
+	if err != nil {
+		return Runtime{}, err
+	}
+	if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return Runtime{}, errors.New("java is not an executable file")
+	}
+
+	res := Runtime{
+		Path: javaPath,
+		Home: filepath.Dir(filepath.Dir(javaPath)),
+	}
+
+	if release, err := readRelease(filepath.Join(res.Home, "release")); err == nil && release["JAVA_VERSION"] != "" {
+		res.Version = release["JAVA_VERSION"]
+		res.Vendor = release["IMPLEMENTOR"]
+	} else {
+		res.Version, err = runVersion(javaPath)
+		if err != nil {
+			return Runtime{}, err
+		}
+	}
+
+	res.MajorVersion = ParseMajorVersion(res.Version)
+	if res.MajorVersion == 0 {
+		return Runtime{}, errors.New("cannot tell the java version")
+	}
+
+	return res, nil
+}
+
+// ParseMajorVersion turns both 1.8.0_292 and 17.0.2 style versions into their major version
+func ParseMajorVersion(version string) int {
+	parts := strings.FieldsFunc(version, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == '+'
+	})
+	if len(parts) == 0 {
+		return 0
+	}
+
+	major, _ := strconv.Atoi(parts[0])
+	if major == 1 && len(parts) > 1 {
+		major, _ = strconv.Atoi(parts[1])
+	}
+
+	return major
+}
+
+// readRelease parses the KEY="value" lines of the release file of a java home
+func readRelease(filePath string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		res[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return res, scanner.Err()
+}
+
+func runVersion(javaPath string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	// java prints its version on stderr
+	out, err := exec.CommandContext(ctx, javaPath, "-version").CombinedOutput()
+	if err != nil {
+		return "", err
+	}
+
+	match := versionOutputRegex.FindSubmatch(out)
+	if match == nil {
+		return "", errors.New("cannot tell the java version")
+	}
+
+	return string(match[1]), nil
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// cgroupMemoryLimitFiles hold the memory limit of the cgroup the api runs in, for cgroup v2 and v1
+var cgroupMemoryLimitFiles = []string{
+	"/sys/fs/cgroup/memory.max",
+	"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+}
+
+type MemoryInfo struct {
+	// TotalBytes is the memory of the host, or the memory limit of the cgroup when lower
+	TotalBytes uint64
+	// AvailableBytes is what the kernel estimates can be allocated right now without swapping
+	AvailableBytes uint64
+}
+
+// GetMemoryInfo reads the memory of the host from /proc/meminfo, capped by the cgroup limit when there is one
+func GetMemoryInfo() (MemoryInfo, error) {
+	data, err := ioutil.ReadFile("/proc/meminfo")
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	var res MemoryInfo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		// values are in kB
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[0] {
+		case "MemTotal:":
+			res.TotalBytes = v * 1024
+		case "MemAvailable:":
+			res.AvailableBytes = v * 1024
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return MemoryInfo{}, err
+	}
+	if res.TotalBytes == 0 {
+		return MemoryInfo{}, errors.New("cannot tell the memory of the host")
+	}
+
+	if limit := getCgroupMemoryLimit(); limit > 0 && limit < res.TotalBytes {
+		res.TotalBytes = limit
+	}
+	if res.AvailableBytes > res.TotalBytes {
+		res.AvailableBytes = res.TotalBytes
+	}
+
+	return res, nil
+}
+
+// getCgroupMemoryLimit returns the memory limit of the cgroup, 0 when unlimited or unknown
+func getCgroupMemoryLimit() uint64 {
+	for _, filePath := range cgroupMemoryLimitFiles {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+
+		// cgroup v2 writes max when unlimited, v1 writes a huge number that is above the host memory anyway
+		v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		return v
+	}
+
+	return 0
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsProcessAlive reports whether the process exists and has not become a zombie
+func IsProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%v/stat", pid))
+	if err != nil {
+		return false
+	}
+
+	// the state comes right after the command name, which is wrapped in parentheses
+	idx := bytes.LastIndexByte(data, ')')
+	if idx < 0 || idx+2 >= len(data) {
+		return false
+	}
+
+	return data[idx+2] != 'Z' && data[idx+2] != 'X'
+}
+
+func GetProcessCwd(pid int) (string, error) {
+	return os.Readlink(fmt.Sprintf("/proc/%v/cwd", pid))
+}
+
+func GetProcessCmdline(pid int) ([]string, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%v/cmdline", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00"), nil
+}
+
+// userHZ is the unit of the cpu times in /proc, fixed at 100 on the architectures linux runs on
+const userHZ = 100
+
+// ProcessStat is a reading of /proc/<pid> of a running process
+type ProcessStat struct {
+	// CPUTime is the user and system time the process used since it started
+	CPUTime  time.Duration
+	RSSBytes uint64
+	Threads  int
+	OpenFDs  int
+	Uptime   time.Duration
+}
+
+func GetProcessStat(pid int) (ProcessStat, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%v/stat", pid))
+	if err != nil {
+		return ProcessStat{}, err
+	}
+
+	// fields are counted from the state, which comes right after the command name wrapped in parentheses
+	idx := bytes.LastIndexByte(data, ')')
+	if idx < 0 {
+		return ProcessStat{}, errors.New("malformed process stat")
+	}
+	fields := strings.Fields(string(data[idx+1:]))
+	if len(fields) < 22 {
+		return ProcessStat{}, errors.New("malformed process stat")
+	}
+
+	var values [22]uint64
+	for _, i := range []int{11, 12, 17, 19, 21} {
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return ProcessStat{}, errors.New("malformed process stat")
+		}
+	}
+
+	uptime, err := getSystemUptime()
+	if err != nil {
+		return ProcessStat{}, err
+	}
+
+	res := ProcessStat{
+		CPUTime:  time.Duration(values[11]+values[12]) * time.Second / userHZ,
+		Threads:  int(values[17]),
+		RSSBytes: values[21] * uint64(os.Getpagesize()),
+		Uptime:   uptime - time.Duration(values[19])*time.Second/userHZ,
+	}
+
+	// the fds of a process owned by another user cannot be listed, leave them at 0 then
+	if fds, err := ioutil.ReadDir(fmt.Sprintf("/proc/%v/fd", pid)); err == nil {
+		res.OpenFDs = len(fds)
+	}
+
+	return res, nil
+}
+
+// getSystemUptime returns the time since boot from /proc/uptime
+func getSystemUptime() (time.Duration, error) {
+	data, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errors.New("malformed uptime")
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
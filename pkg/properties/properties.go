@@ -0,0 +1,296 @@
+package properties
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+type line struct {
+	key     string
+	raw     string
+	isEntry bool
+}
+
+// Properties is a java .properties file that keeps comments, blank lines and ordering when it is written back
+type Properties struct {
+	lines  []line
+	values map[string]string
+}
+
+func New() *Properties {
+	return &Properties{
+		values: make(map[string]string),
+	}
+}
+
+func Parse(data []byte) *Properties {
+	var res = New()
+	raws := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i := 0; i < len(raws); i++ {
+		raw := strings.TrimRight(raws[i], "\r")
+
+		trimmed := strings.TrimLeft(raw, " \t\f")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!") {
+			res.lines = append(res.lines, line{raw: raw})
+			continue
+		}
+
+		// an odd number of trailing backslashes continues the entry on the next line, whose leading whitespace is
+		// dropped, the raw lines are kept as they are so the entry is written back unchanged
+		logical := trimmed
+		for isContinued(logical) {
+			logical = logical[:len(logical)-1]
+			if i+1 >= len(raws) {
+				break
+			}
+			i++
+			next := strings.TrimRight(raws[i], "\r")
+			raw += "\n" + next
+			logical += strings.TrimLeft(next, " \t\f")
+		}
+
+		key, value := splitEntry(logical)
+		if _, ok := res.values[key]; ok {
+			// the last duplicate wins like in java, it takes the place of the first one
+			for j := range res.lines {
+				if res.lines[j].isEntry && res.lines[j].key == key {
+					res.lines[j].raw = raw
+				}
+			}
+		} else {
+			res.lines = append(res.lines, line{key: key, raw: raw, isEntry: true})
+		}
+		res.values[key] = value
+	}
+
+	return res
+}
+
+func isContinued(s string) bool {
+	var n int
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// Load reads the file, a missing file gives empty properties
+func Load(filePath string) (*Properties, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, err
+	}
+
+	return Parse(data), nil
+}
+
+func (p *Properties) Get(key string) (string, bool) {
+	value, ok := p.values[key]
+	return value, ok
+}
+
+// Set replaces the value in place, new keys are appended at the end
+func (p *Properties) Set(key, value string) {
+	raw := escape(key, true) + "=" + escape(value, false)
+	if _, ok := p.values[key]; ok {
+		for i := range p.lines {
+			if p.lines[i].isEntry && p.lines[i].key == key {
+				p.lines[i].raw = raw
+			}
+		}
+	} else {
+		p.lines = append(p.lines, line{key: key, raw: raw, isEntry: true})
+	}
+
+	p.values[key] = value
+}
+
+func (p *Properties) Delete(key string) {
+	if _, ok := p.values[key]; !ok {
+		return
+	}
+
+	var lines []line
+	for _, l := range p.lines {
+		if l.isEntry && l.key == key {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	p.lines = lines
+
+	delete(p.values, key)
+}
+
+// Keys returns the keys in file order
+func (p *Properties) Keys() []string {
+	var res []string
+	for _, l := range p.lines {
+		if l.isEntry {
+			res = append(res, l.key)
+		}
+	}
+
+	return res
+}
+
+func (p *Properties) Map() map[string]string {
+	var res = make(map[string]string, len(p.values))
+	for k, v := range p.values {
+		res[k] = v
+	}
+
+	return res
+}
+
+func (p *Properties) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, l := range p.lines {
+		buf.WriteString(l.raw)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// Save writes the file through a temporary file so it is never left half written
+func (p *Properties) Save(filePath string) error {
+	if err := ioutil.WriteFile(filePath+".tmp", p.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(filePath+".tmp", filePath)
+}
+
+// splitEntry splits on the first unescaped separator, which is either '=', ':' or whitespace
+func splitEntry(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t':
+			key := s[:i]
+			value := strings.TrimLeft(s[i:], " \t")
+			if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+				value = strings.TrimLeft(value[1:], " \t")
+			}
+			return unescape(key), unescape(value)
+		}
+	}
+
+	return unescape(s), ""
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	var high rune
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				var r rune
+				isValid := true
+				for _, c := range s[i+1 : i+5] {
+					r <<= 4
+					switch {
+					case c >= '0' && c <= '9':
+						r |= c - '0'
+					case c >= 'a' && c <= 'f':
+						r |= c - 'a' + 10
+					case c >= 'A' && c <= 'F':
+						r |= c - 'A' + 10
+					default:
+						isValid = false
+					}
+				}
+				if isValid {
+					i += 4
+					// a high surrogate waits for its low half
+					if utf16.IsSurrogate(r) && r < 0xdc00 {
+						high = r
+						continue
+					}
+					if high != 0 {
+						r = utf16.DecodeRune(high, r)
+						high = 0
+					}
+					sb.WriteRune(r)
+					continue
+				}
+			}
+			sb.WriteByte('u')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return sb.String()
+}
+
+// escape follows java Properties.store, isKey also escapes spaces that would otherwise end the key
+func escape(s string, isKey bool) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\', '=', ':', '#', '!':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case ' ':
+			if isKey || i == 0 {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				if r > 0xffff {
+					r1, r2 := utf16.EncodeRune(r)
+					sb.WriteString(`\u` + hex4(r1) + `\u` + hex4(r2))
+					continue
+				}
+				sb.WriteString(`\u` + hex4(r))
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+func hex4(r rune) string {
+	const digits = "0123456789ABCDEF"
+	return string([]byte{digits[r>>12&0xf], digits[r>>8&0xf], digits[r>>4&0xf], digits[r&0xf]})
+}
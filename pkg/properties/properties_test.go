@@ -0,0 +1,179 @@
+package properties
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantKeys []string
+		want     map[string]string
+	}{
+		{name: "empty", data: "", wantKeys: nil, want: map[string]string{}},
+		{name: "equal separator", data: "motd=A Minecraft Server\n", wantKeys: []string{"motd"}, want: map[string]string{"motd": "A Minecraft Server"}},
+		{name: "colon separator", data: "motd:hello", wantKeys: []string{"motd"}, want: map[string]string{"motd": "hello"}},
+		{name: "whitespace separator", data: "motd   hello world", wantKeys: []string{"motd"}, want: map[string]string{"motd": "hello world"}},
+		{name: "whitespace around separator", data: "  motd  =  hello", wantKeys: []string{"motd"}, want: map[string]string{"motd": "hello"}},
+		{name: "empty value", data: "level-seed=", wantKeys: []string{"level-seed"}, want: map[string]string{"level-seed": ""}},
+		{name: "key only", data: "level-seed", wantKeys: []string{"level-seed"}, want: map[string]string{"level-seed": ""}},
+		{name: "trailing value whitespace is kept", data: "motd=hi  ", wantKeys: []string{"motd"}, want: map[string]string{"motd": "hi  "}},
+		{name: "crlf", data: "a=1\r\nb=2\r\n", wantKeys: []string{"a", "b"}, want: map[string]string{"a": "1", "b": "2"}},
+		{name: "comments and blanks", data: "#comment\n! other\n\n  # indented\na=1", wantKeys: []string{"a"}, want: map[string]string{"a": "1"}},
+		{name: "escaped separators in key", data: `a\=b\:c\ d=value`, wantKeys: []string{"a=b:c d"}, want: map[string]string{"a=b:c d": "value"}},
+		{name: "escaped characters", data: `motd=tab\there\nnew\\slash\#hash`, wantKeys: []string{"motd"}, want: map[string]string{"motd": "tab\there\nnew\\slash#hash"}},
+		{name: "unicode escape", data: `motd=\u00A7aGreen \u00e9`, wantKeys: []string{"motd"}, want: map[string]string{"motd": "§aGreen é"}},
+		{name: "surrogate pair escape", data: `motd=\uD83D\uDE00`, wantKeys: []string{"motd"}, want: map[string]string{"motd": "😀"}},
+		{name: "invalid unicode escape", data: `motd=\uZZZZ`, wantKeys: []string{"motd"}, want: map[string]string{"motd": "uZZZZ"}},
+		{name: "continuation", data: "motd=hello \\\n    world\nb=2", wantKeys: []string{"motd", "b"}, want: map[string]string{"motd": "hello world", "b": "2"}},
+		{name: "several continuations", data: "list=a,\\\n  b,\\\n  c", wantKeys: []string{"list"}, want: map[string]string{"list": "a,b,c"}},
+		{name: "continuation in key", data: "lo\\\n  ng=1", wantKeys: []string{"long"}, want: map[string]string{"long": "1"}},
+		{name: "escaped backslash is no continuation", data: "path=C:\\\\\nb=2", wantKeys: []string{"path", "b"}, want: map[string]string{"path": `C:\`, "b": "2"}},
+		{name: "continuation at the end of the file", data: "a=1\\", wantKeys: []string{"a"}, want: map[string]string{"a": "1"}},
+		{name: "comment does not continue", data: "# comment \\\na=1", wantKeys: []string{"a"}, want: map[string]string{"a": "1"}},
+		{name: "last duplicate wins", data: "a=1\nb=2\na=3", wantKeys: []string{"a", "b"}, want: map[string]string{"a": "3", "b": "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Parse([]byte(tt.data))
+
+			if got := p.Keys(); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Keys() = %q, want %q", got, tt.wantKeys)
+			}
+			if got := p.Map(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Map() = %q, want %q", got, tt.want)
+			}
+
+			// what is written back parses to the same values
+			if got := Parse(p.Bytes()).Map(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(Bytes()).Map() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytesKeepsLayout(t *testing.T) {
+	data := "#Minecraft server properties\n#Mon Jan 02 15:04:05 UTC 2006\n\nmotd=hello \\\n    world\ndifficulty=easy\n# keep me\npvp=true\n"
+
+	p := Parse([]byte(data))
+	if got := string(p.Bytes()); got != data {
+		t.Errorf("Bytes() = %q, want %q", got, data)
+	}
+}
+
+func TestSetDelete(t *testing.T) {
+	data := "#header\nmotd=hello \\\n    world\n\ndifficulty=easy\n# keep me\npvp=true\n"
+
+	tests := []struct {
+		name   string
+		update func(p *Properties)
+		want   string
+	}{
+		{
+			name:   "set existing key in place",
+			update: func(p *Properties) { p.Set("difficulty", "hard") },
+			want:   "#header\nmotd=hello \\\n    world\n\ndifficulty=hard\n# keep me\npvp=true\n",
+		},
+		{
+			name:   "set continued key replaces every line of it",
+			update: func(p *Properties) { p.Set("motd", "bye") },
+			want:   "#header\nmotd=bye\n\ndifficulty=easy\n# keep me\npvp=true\n",
+		},
+		{
+			name:   "set new key appends",
+			update: func(p *Properties) { p.Set("level-seed", "42") },
+			want:   data + "level-seed=42\n",
+		},
+		{
+			name:   "delete key keeps comments",
+			update: func(p *Properties) { p.Delete("difficulty") },
+			want:   "#header\nmotd=hello \\\n    world\n\n# keep me\npvp=true\n",
+		},
+		{
+			name:   "delete continued key",
+			update: func(p *Properties) { p.Delete("motd") },
+			want:   "#header\n\ndifficulty=easy\n# keep me\npvp=true\n",
+		},
+		{
+			name:   "delete missing key",
+			update: func(p *Properties) { p.Delete("level-seed") },
+			want:   data,
+		},
+		{
+			name: "set escapes",
+			update: func(p *Properties) {
+				p.Set("a b=c", " lead\tsep=:#!\\ §😀")
+			},
+			want: data + `a\ b\=c=\ lead\tsep\=\:\#\!\\ \u00A7\uD83D\uDE00` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Parse([]byte(data))
+			tt.update(p)
+
+			if got := string(p.Bytes()); got != tt.want {
+				t.Errorf("Bytes() = %q, want %q", got, tt.want)
+			}
+			if got := Parse(p.Bytes()).Map(); !reflect.DeepEqual(got, p.Map()) {
+				t.Errorf("Parse(Bytes()).Map() = %q, want %q", got, p.Map())
+			}
+		})
+	}
+}
+
+func TestEscapeRoundTrip(t *testing.T) {
+	values := []string{
+		"",
+		"plain",
+		" leading space",
+		"trailing space ",
+		"a=b:c#d!e",
+		`back\slash`,
+		"tab\tnew\nline\rfeed\f",
+		"§6gold",
+		"😀 emoji",
+		"\u0001control",
+	}
+
+	for _, value := range values {
+		p := New()
+		p.Set(value+"key", value)
+
+		got := Parse(p.Bytes())
+		if v, ok := got.Get(value + "key"); !ok || v != value {
+			t.Errorf("round trip of %q = %q (found %v)", value, v, ok)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "server.properties")
+
+	p, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	if len(p.Keys()) != 0 {
+		t.Errorf("Load() of a missing file has keys %q", p.Keys())
+	}
+
+	p.Set("motd", "hello")
+	p.Set("pvp", "false")
+	if err := p.Save(filePath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Map(), p.Map()) || !reflect.DeepEqual(got.Keys(), []string{"motd", "pvp"}) {
+		t.Errorf("Load() = %q in order %q", got.Map(), got.Keys())
+	}
+}
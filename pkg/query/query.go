@@ -0,0 +1,186 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	packetTypeHandshake = 0x09
+	packetTypeStat      = 0x00
+
+	maxPacketSize = 1 << 16
+)
+
+var magic = []byte{0xFE, 0xFD}
+
+// FullStat is the full stat answer of the query protocol
+type FullStat struct {
+	MOTD       string `json:"motd"`
+	GameType   string `json:"game_type"`
+	GameID     string `json:"game_id"`
+	Version    string `json:"version"`
+	Map        string `json:"map"`
+	NumPlayers int    `json:"num_players"`
+	MaxPlayers int    `json:"max_players"`
+	HostIP     string `json:"host_ip"`
+	HostPort   int    `json:"host_port"`
+	// ServerMod and Plugins are split from the plugins value, vanilla servers leave both empty
+	ServerMod string   `json:"server_mod,omitempty"`
+	Plugins   []string `json:"plugins"`
+	Players   []string `json:"players"`
+	// Values holds every key value pair as sent by the server
+	Values map[string]string `json:"values"`
+}
+
+// Query requests the full stat of the server listening on the udp address
+func Query(address string, timeout time.Duration) (*FullStat, error) {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	sessionID := rand.Int31() & 0x0F0F0F0F
+
+	// handshake
+	if err := writePacket(conn, packetTypeHandshake, sessionID, nil); err != nil {
+		return nil, err
+	}
+	data, err := readPacket(conn, packetTypeHandshake, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	challenge, err := strconv.ParseInt(string(bytes.TrimRight(data, "\x00")), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid challenge token: %v", err)
+	}
+
+	// full stat, the 4 trailing bytes of padding are what distinguish it from the basic stat
+	var payload bytes.Buffer
+	binary.Write(&payload, binary.BigEndian, int32(challenge))
+	payload.Write([]byte{0, 0, 0, 0})
+	if err := writePacket(conn, packetTypeStat, sessionID, payload.Bytes()); err != nil {
+		return nil, err
+	}
+	data, err = readPacket(conn, packetTypeStat, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFullStat(data)
+}
+
+func writePacket(conn net.Conn, typ byte, sessionID int32, payload []byte) error {
+	var buf bytes.Buffer
+	buf.Write(magic)
+	buf.WriteByte(typ)
+	binary.Write(&buf, binary.BigEndian, sessionID)
+	buf.Write(payload)
+
+	_, err := conn.Write(buf.Bytes())
+	return err
+}
+
+func readPacket(conn net.Conn, typ byte, sessionID int32) ([]byte, error) {
+	buf := make([]byte, maxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	if len(buf) < 5 || buf[0] != typ || int32(binary.BigEndian.Uint32(buf[1:5])) != sessionID {
+		return nil, errors.New("unexpected query response")
+	}
+
+	return buf[5:], nil
+}
+
+// parseFullStat reads the padded key value section followed by the padded player section
+func parseFullStat(data []byte) (*FullStat, error) {
+	// splitnum\x00\x80\x00
+	if len(data) < 11 {
+		return nil, errors.New("full stat response is too short")
+	}
+	data = data[11:]
+
+	res := &FullStat{
+		Plugins: []string{},
+		Players: []string{},
+		Values:  map[string]string{},
+	}
+
+	for {
+		key, rest, ok := readString(data)
+		if !ok {
+			return nil, errors.New("truncated full stat key")
+		}
+		data = rest
+		if key == "" {
+			break
+		}
+
+		value, rest, ok := readString(data)
+		if !ok {
+			return nil, errors.New("truncated full stat value")
+		}
+		data = rest
+		res.Values[key] = value
+	}
+
+	// \x01player_\x00\x00
+	if len(data) >= 10 {
+		data = data[10:]
+		for {
+			name, rest, ok := readString(data)
+			if !ok || name == "" {
+				break
+			}
+			data = rest
+			res.Players = append(res.Players, name)
+		}
+	}
+
+	res.MOTD = res.Values["hostname"]
+	res.GameType = res.Values["gametype"]
+	res.GameID = res.Values["game_id"]
+	res.Version = res.Values["version"]
+	res.Map = res.Values["map"]
+	res.HostIP = res.Values["hostip"]
+	res.NumPlayers, _ = strconv.Atoi(res.Values["numplayers"])
+	res.MaxPlayers, _ = strconv.Atoi(res.Values["maxplayers"])
+	res.HostPort, _ = strconv.Atoi(res.Values["hostport"])
+
+	// plugins come as "<server mod>: <plugin>; <plugin>"
+	if plugins := res.Values["plugins"]; plugins != "" {
+		serverMod, list, isFound := strings.Cut(plugins, ": ")
+		res.ServerMod = strings.TrimSpace(serverMod)
+		if isFound {
+			for _, plugin := range strings.Split(list, "; ") {
+				if plugin = strings.TrimSpace(plugin); plugin != "" {
+					res.Plugins = append(res.Plugins, plugin)
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func readString(data []byte) (string, []byte, bool) {
+	idx := bytes.IndexByte(data, 0)
+	if idx < 0 {
+		return "", nil, false
+	}
+
+	return string(data[:idx]), data[idx+1:], true
+}
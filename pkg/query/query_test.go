@@ -0,0 +1,256 @@
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testTimeout   = 2 * time.Second
+	testChallenge = 9513307
+)
+
+// startFakeServer answers every udp packet with what respond returns, nothing is sent back when it returns nil
+func startFakeServer(t *testing.T, respond func(typ byte, sessionID int32, payload []byte) []byte) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 7 || !bytes.Equal(buf[:2], magic) {
+				t.Errorf("invalid query request % x", buf[:n])
+				continue
+			}
+
+			sessionID := int32(binary.BigEndian.Uint32(buf[3:7]))
+			if res := respond(buf[2], sessionID, append([]byte{}, buf[7:n]...)); res != nil {
+				conn.WriteTo(res, addr)
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		conn.Close()
+		<-done
+	})
+
+	return conn.LocalAddr().String()
+}
+
+func response(typ byte, sessionID int32, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(typ)
+	binary.Write(&buf, binary.BigEndian, sessionID)
+	buf.Write(payload)
+
+	return buf.Bytes()
+}
+
+// fullStatPayload builds the full stat answer from the ordered key value pairs and the players
+func fullStatPayload(values [][2]string, players []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("splitnum\x00\x80\x00")
+	for _, kv := range values {
+		buf.WriteString(kv[0] + "\x00" + kv[1] + "\x00")
+	}
+	buf.WriteString("\x00\x01player_\x00\x00")
+	for _, player := range players {
+		buf.WriteString(player + "\x00")
+	}
+	buf.WriteString("\x00")
+
+	return buf.Bytes()
+}
+
+// answerStat hands out the challenge token and answers the full stat requests carrying it with stat
+func answerStat(t *testing.T, stat []byte) func(typ byte, sessionID int32, payload []byte) []byte {
+	return func(typ byte, sessionID int32, payload []byte) []byte {
+		switch typ {
+		case packetTypeHandshake:
+			return response(typ, sessionID, []byte("9513307\x00"))
+		case packetTypeStat:
+			if len(payload) != 8 {
+				t.Errorf("full stat request payload is %v bytes, want 8", len(payload))
+				return nil
+			}
+			if challenge := int32(binary.BigEndian.Uint32(payload[:4])); challenge != testChallenge {
+				t.Errorf("challenge token = %v, want %v", challenge, testChallenge)
+				return nil
+			}
+			return response(typ, sessionID, stat)
+		}
+		return nil
+	}
+}
+
+var testValues = [][2]string{
+	{"hostname", "A Minecraft Server"},
+	{"gametype", "SMP"},
+	{"game_id", "MINECRAFT"},
+	{"version", "1.19.2"},
+	{"plugins", "Paper on 1.19.2-R0.1-SNAPSHOT: WorldEdit 7.2.12; Essentials 2.19.7"},
+	{"map", "world"},
+	{"numplayers", "2"},
+	{"maxplayers", "20"},
+	{"hostport", "25565"},
+	{"hostip", "127.0.0.1"},
+}
+
+func TestQuery(t *testing.T) {
+	address := startFakeServer(t, answerStat(t, fullStatPayload(testValues, []string{"steve", "alex"})))
+
+	res, err := Query(address, testTimeout)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	want := &FullStat{
+		MOTD:       "A Minecraft Server",
+		GameType:   "SMP",
+		GameID:     "MINECRAFT",
+		Version:    "1.19.2",
+		Map:        "world",
+		NumPlayers: 2,
+		MaxPlayers: 20,
+		HostIP:     "127.0.0.1",
+		HostPort:   25565,
+		ServerMod:  "Paper on 1.19.2-R0.1-SNAPSHOT",
+		Plugins:    []string{"WorldEdit 7.2.12", "Essentials 2.19.7"},
+		Players:    []string{"steve", "alex"},
+		Values:     map[string]string{},
+	}
+	for _, kv := range testValues {
+		want.Values[kv[0]] = kv[1]
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("Query() = %+v, want %+v", res, want)
+	}
+}
+
+func TestParseFullStatPlugins(t *testing.T) {
+	tests := []struct {
+		name          string
+		plugins       string
+		wantServerMod string
+		wantPlugins   []string
+	}{
+		{name: "vanilla", plugins: "", wantServerMod: "", wantPlugins: []string{}},
+		{name: "server mod without plugins", plugins: "CraftBukkit on Bukkit 1.19.2", wantServerMod: "CraftBukkit on Bukkit 1.19.2", wantPlugins: []string{}},
+		{name: "single plugin", plugins: "Paper on 1.19.2: LuckPerms 5.4", wantServerMod: "Paper on 1.19.2", wantPlugins: []string{"LuckPerms 5.4"}},
+		{name: "several plugins", plugins: "Paper on 1.19.2: A 1; B 2; C 3", wantServerMod: "Paper on 1.19.2", wantPlugins: []string{"A 1", "B 2", "C 3"}},
+		{name: "empty plugin entries", plugins: "Paper on 1.19.2: A 1; ; B 2; ", wantServerMod: "Paper on 1.19.2", wantPlugins: []string{"A 1", "B 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := parseFullStat(fullStatPayload([][2]string{{"hostname", "x"}, {"plugins", tt.plugins}}, nil))
+			if err != nil {
+				t.Fatalf("parseFullStat() error = %v", err)
+			}
+			if res.ServerMod != tt.wantServerMod {
+				t.Errorf("ServerMod = %q, want %q", res.ServerMod, tt.wantServerMod)
+			}
+			if !reflect.DeepEqual(res.Plugins, tt.wantPlugins) {
+				t.Errorf("Plugins = %q, want %q", res.Plugins, tt.wantPlugins)
+			}
+		})
+	}
+}
+
+func TestQueryInvalidResponse(t *testing.T) {
+	stat := fullStatPayload(testValues, []string{"steve"})
+
+	tests := []struct {
+		name    string
+		respond func(typ byte, sessionID int32, payload []byte) []byte
+		wantErr string
+	}{
+		{
+			name: "non numeric challenge token",
+			respond: func(typ byte, sessionID int32, payload []byte) []byte {
+				return response(typ, sessionID, []byte("abc\x00"))
+			},
+			wantErr: "invalid challenge token",
+		},
+		{
+			name: "handshake of another session",
+			respond: func(typ byte, sessionID int32, payload []byte) []byte {
+				return response(typ, sessionID+1, []byte("9513307\x00"))
+			},
+			wantErr: "unexpected query response",
+		},
+		{
+			name: "response without a header",
+			respond: func(typ byte, sessionID int32, payload []byte) []byte {
+				return []byte{typ, 0}
+			},
+			wantErr: "unexpected query response",
+		},
+		{
+			name: "stat answered with the wrong type",
+			respond: func(typ byte, sessionID int32, payload []byte) []byte {
+				if typ == packetTypeHandshake {
+					return response(typ, sessionID, []byte("9513307\x00"))
+				}
+				return response(packetTypeHandshake, sessionID, stat)
+			},
+			wantErr: "unexpected query response",
+		},
+		{name: "stat shorter than the padding", respond: answerStat(t, stat[:5]), wantErr: "too short"},
+		{name: "stat without key values", respond: answerStat(t, stat[:11]), wantErr: "truncated full stat key"},
+		{name: "truncated key", respond: answerStat(t, stat[:14]), wantErr: "truncated full stat key"},
+		{name: "truncated value", respond: answerStat(t, stat[:22]), wantErr: "truncated full stat value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startFakeServer(t, tt.respond)
+
+			if _, err := Query(address, testTimeout); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Query() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseFullStatTruncated(t *testing.T) {
+	stat := fullStatPayload(testValues, []string{"steve", "alex"})
+
+	// every prefix either parses or fails, none of them panics
+	for i := 0; i < len(stat); i++ {
+		parseFullStat(stat[:i])
+	}
+
+	// garbage after the padding has no terminating null
+	if _, err := parseFullStat(append(stat[:11:11], bytes.Repeat([]byte{0xFF}, 64)...)); err == nil {
+		t.Error("parseFullStat() of garbage error = nil, want an error")
+	}
+
+	// a player section cut in the middle keeps the players read so far
+	cut := bytes.LastIndex(stat, []byte("alex"))
+	res, err := parseFullStat(stat[:cut+2])
+	if err != nil {
+		t.Fatalf("parseFullStat() error = %v", err)
+	}
+	if want := []string{"steve"}; !reflect.DeepEqual(res.Players, want) {
+		t.Errorf("Players = %q, want %q", res.Players, want)
+	}
+}
@@ -0,0 +1,169 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	packetTypeResponse = 0
+	packetTypeCommand  = 2
+	packetTypeLogin    = 3
+	// packetTypeMarker is unknown to the server, its reply marks the end of a fragmented response
+	packetTypeMarker = 100
+
+	maxPacketSize = 4110
+	// maxResponseSize is lenient as some servers do not fragment their responses
+	maxResponseSize = 1 << 20
+)
+
+var ErrAuthFailed = errors.New("rcon authentication failed")
+
+// Client speaks the source rcon protocol, it is safe for concurrent use
+type Client struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	timeout time.Duration
+	nextID  int32
+}
+
+type packet struct {
+	id   int32
+	typ  int32
+	body string
+}
+
+// Dial connects and authenticates, timeout bounds the connection and every later round trip
+func Dial(address, password string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:    conn,
+		timeout: timeout,
+	}
+
+	if err := c.login(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Client) login(password string) error {
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+
+	id := c.newID()
+	if err := c.write(packet{id: id, typ: packetTypeLogin, body: password}); err != nil {
+		return err
+	}
+
+	for {
+		res, err := c.read()
+		if err != nil {
+			return err
+		}
+
+		// some servers send an empty response value before the auth response
+		if res.typ == packetTypeResponse {
+			continue
+		}
+
+		if res.id == -1 || res.id != id {
+			return ErrAuthFailed
+		}
+
+		return nil
+	}
+}
+
+// Execute runs the command and returns its response, joining fragments of long responses
+func (c *Client) Execute(command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+
+	id := c.newID()
+	if err := c.write(packet{id: id, typ: packetTypeCommand, body: command}); err != nil {
+		return "", err
+	}
+
+	markerID := c.newID()
+	if err := c.write(packet{id: markerID, typ: packetTypeMarker}); err != nil {
+		return "", err
+	}
+
+	var res bytes.Buffer
+	for {
+		p, err := c.read()
+		if err != nil {
+			return "", err
+		}
+
+		if p.id == markerID {
+			return res.String(), nil
+		}
+
+		if p.id == id {
+			res.WriteString(p.body)
+		}
+	}
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) newID() int32 {
+	c.nextID++
+	return c.nextID
+}
+
+func (c *Client) write(p packet) error {
+	length := int32(4 + 4 + len(p.body) + 2)
+	if length > maxPacketSize {
+		return fmt.Errorf("rcon packet cannot > %v bytes", maxPacketSize)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, length)
+	binary.Write(&buf, binary.LittleEndian, p.id)
+	binary.Write(&buf, binary.LittleEndian, p.typ)
+	buf.WriteString(p.body)
+	buf.Write([]byte{0, 0})
+
+	_, err := c.conn.Write(buf.Bytes())
+	return err
+}
+
+func (c *Client) read() (packet, error) {
+	var length int32
+	if err := binary.Read(c.conn, binary.LittleEndian, &length); err != nil {
+		return packet{}, err
+	}
+
+	if length < 10 || length > maxResponseSize {
+		return packet{}, fmt.Errorf("invalid rcon packet length %v", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, data); err != nil {
+		return packet{}, err
+	}
+
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		typ:  int32(binary.LittleEndian.Uint32(data[4:8])),
+		body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
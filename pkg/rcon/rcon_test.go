@@ -0,0 +1,225 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+// fakeServer accepts a single connection and hands every packet it receives to handle
+type fakeServer struct {
+	listener net.Listener
+	done     chan struct{}
+}
+
+func newFakeServer(t *testing.T, handle func(conn net.Conn, p packet)) *fakeServer {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := &fakeServer{listener: l, done: make(chan struct{})}
+	go func() {
+		defer close(fs.done)
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			p, err := readTestPacket(conn)
+			if err != nil {
+				return
+			}
+			handle(conn, p)
+		}
+	}()
+
+	t.Cleanup(func() {
+		l.Close()
+		<-fs.done
+	})
+
+	return fs
+}
+
+func (fs *fakeServer) address() string {
+	return fs.listener.Addr().String()
+}
+
+func readTestPacket(r io.Reader) (packet, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return packet{}, err
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return packet{}, err
+	}
+
+	return packet{
+		id:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		typ:  int32(binary.LittleEndian.Uint32(data[4:8])),
+		body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
+
+func writeTestPacket(w io.Writer, p packet) {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, int32(4+4+len(p.body)+2))
+	binary.Write(&buf, binary.LittleEndian, p.id)
+	binary.Write(&buf, binary.LittleEndian, p.typ)
+	buf.WriteString(p.body)
+	buf.Write([]byte{0, 0})
+	w.Write(buf.Bytes())
+}
+
+// answerLogin accepts the password, sending the empty response value some servers send first
+func answerLogin(conn net.Conn, p packet, password string) bool {
+	if p.typ != packetTypeLogin {
+		return false
+	}
+
+	writeTestPacket(conn, packet{id: p.id, typ: packetTypeResponse})
+	if p.body != password {
+		writeTestPacket(conn, packet{id: -1, typ: packetTypeCommand})
+		return true
+	}
+	writeTestPacket(conn, packet{id: p.id, typ: packetTypeCommand})
+
+	return true
+}
+
+// answerMarker replies to the marker like minecraft does to unknown packet types
+func answerMarker(conn net.Conn, p packet) bool {
+	if p.typ != packetTypeMarker {
+		return false
+	}
+
+	writeTestPacket(conn, packet{id: p.id, typ: packetTypeResponse, body: "Unknown request 64"})
+	return true
+}
+
+func TestDialAuth(t *testing.T) {
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		answerLogin(conn, p, "secret")
+	})
+
+	c, err := Dial(fs.address(), "secret", testTimeout)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	c.Close()
+}
+
+func TestDialAuthFailed(t *testing.T) {
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		answerLogin(conn, p, "secret")
+	})
+
+	_, err := Dial(fs.address(), "wrong", testTimeout)
+	if !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("Dial() error = %v, want %v", err, ErrAuthFailed)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		if answerLogin(conn, p, "secret") || answerMarker(conn, p) {
+			return
+		}
+		if p.body == "list" {
+			writeTestPacket(conn, packet{id: p.id, typ: packetTypeResponse, body: "There are 0 of a max of 20 players online: "})
+		}
+	})
+
+	c, err := Dial(fs.address(), "secret", testTimeout)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer c.Close()
+
+	got, err := c.Execute("list")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "There are 0 of a max of 20 players online: "; got != want {
+		t.Errorf("Execute() = %q, want %q", got, want)
+	}
+}
+
+func TestExecuteFragmented(t *testing.T) {
+	fragments := []string{strings.Repeat("a", 4096), strings.Repeat("b", 4096), "c"}
+
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		if answerLogin(conn, p, "secret") || answerMarker(conn, p) {
+			return
+		}
+		for _, fragment := range fragments {
+			writeTestPacket(conn, packet{id: p.id, typ: packetTypeResponse, body: fragment})
+		}
+		// a stray packet of another request is ignored
+		writeTestPacket(conn, packet{id: 999, typ: packetTypeResponse, body: "stray"})
+	})
+
+	c, err := Dial(fs.address(), "secret", testTimeout)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer c.Close()
+
+	got, err := c.Execute("help")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := strings.Join(fragments, ""); got != want {
+		t.Errorf("Execute() returned %v bytes, want %v", len(got), len(want))
+	}
+}
+
+func TestExecuteCommandTooLarge(t *testing.T) {
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		answerLogin(conn, p, "secret")
+	})
+
+	c, err := Dial(fs.address(), "secret", testTimeout)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Execute(strings.Repeat("x", maxPacketSize)); err == nil {
+		t.Fatal("Execute() error = nil, want an oversize error")
+	}
+}
+
+func TestExecuteResponseTooLarge(t *testing.T) {
+	fs := newFakeServer(t, func(conn net.Conn, p packet) {
+		if answerLogin(conn, p, "secret") || p.typ == packetTypeMarker {
+			return
+		}
+		binary.Write(conn, binary.LittleEndian, int32(maxResponseSize+1))
+	})
+
+	c, err := Dial(fs.address(), "secret", testTimeout)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Execute("list"); err == nil || !strings.Contains(err.Error(), "invalid rcon packet length") {
+		t.Fatalf("Execute() error = %v, want an invalid length error", err)
+	}
+}
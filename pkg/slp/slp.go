@@ -0,0 +1,245 @@
+package slp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	packetIDHandshake = 0x00
+	packetIDStatus    = 0x00
+	packetIDPing      = 0x01
+
+	// protocolVersion -1 asks the server to answer with its own version
+	protocolVersion = -1
+	nextStateStatus = 1
+
+	maxPacketSize = 1 << 21
+)
+
+type Version struct {
+	Name     string `json:"name"`
+	Protocol int    `json:"protocol"`
+}
+
+type Player struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+type Players struct {
+	Max    int      `json:"max"`
+	Online int      `json:"online"`
+	Sample []Player `json:"sample,omitempty"`
+}
+
+type Status struct {
+	Version Version `json:"version"`
+	Players Players `json:"players"`
+	// MOTD is the plain text of the description, formatting is dropped
+	MOTD string `json:"motd"`
+	// LatencyMS is the round trip of the ping packet
+	LatencyMS int64 `json:"latency_ms"`
+}
+
+// Ping runs the server list ping: a handshake, a status request and a ping to measure the latency
+func Ping(address string, timeout time.Duration) (*Status, error) {
+	host, sPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(sPort)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// handshake
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, protocolVersion)
+	writeString(&handshake, host)
+	binary.Write(&handshake, binary.BigEndian, uint16(port))
+	writeVarInt(&handshake, nextStateStatus)
+	if err := writePacket(conn, packetIDHandshake, handshake.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// status
+	if err := writePacket(conn, packetIDStatus, nil); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	id, data, err := readPacket(reader)
+	if err != nil {
+		return nil, err
+	}
+	if id != packetIDStatus {
+		return nil, fmt.Errorf("unexpected packet %#x in place of status", id)
+	}
+
+	payload, err := readString(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Version     Version         `json:"version"`
+		Players     Players         `json:"players"`
+		Description json.RawMessage `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		return nil, err
+	}
+
+	res := &Status{
+		Version: raw.Version,
+		Players: raw.Players,
+		MOTD:    parseDescription(raw.Description),
+	}
+
+	// ping
+	var ping bytes.Buffer
+	startTime := time.Now()
+	binary.Write(&ping, binary.BigEndian, startTime.UnixNano())
+	if err := writePacket(conn, packetIDPing, ping.Bytes()); err != nil {
+		return nil, err
+	}
+
+	id, data, err = readPacket(reader)
+	if err != nil {
+		return nil, err
+	}
+	if id != packetIDPing || !bytes.Equal(data, ping.Bytes()) {
+		return nil, errors.New("invalid pong")
+	}
+	res.LatencyMS = time.Since(startTime).Milliseconds()
+
+	return res, nil
+}
+
+// parseDescription flattens the description, which is either a plain string or a chat component
+func parseDescription(data json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text
+	}
+
+	var component struct {
+		Text  string            `json:"text"`
+		Extra []json.RawMessage `json:"extra"`
+	}
+	if err := json.Unmarshal(data, &component); err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(component.Text)
+	for _, extra := range component.Extra {
+		sb.WriteString(parseDescription(extra))
+	}
+
+	return sb.String()
+}
+
+func writePacket(w io.Writer, id int, data []byte) error {
+	var body bytes.Buffer
+	writeVarInt(&body, id)
+	body.Write(data)
+
+	var packet bytes.Buffer
+	writeVarInt(&packet, body.Len())
+	packet.Write(body.Bytes())
+
+	_, err := w.Write(packet.Bytes())
+	return err
+}
+
+func readPacket(r *bufio.Reader) (int, []byte, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if length <= 0 || length > maxPacketSize {
+		return 0, nil, fmt.Errorf("invalid packet length %v", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, nil, err
+	}
+
+	body := bytes.NewReader(data)
+	id, err := readVarInt(body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return id, data[len(data)-body.Len():], nil
+}
+
+func writeVarInt(buf *bytes.Buffer, value int) {
+	v := uint32(int32(value))
+	for {
+		if v&^0x7f == 0 {
+			buf.WriteByte(byte(v))
+			return
+		}
+		buf.WriteByte(byte(v&0x7f | 0x80))
+		v >>= 7
+	}
+}
+
+func readVarInt(r io.ByteReader) (int, error) {
+	var res uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		res |= uint32(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return int(int32(res)), nil
+		}
+	}
+
+	return 0, errors.New("varint is too big")
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, len(s))
+	buf.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+	if length < 0 || length > r.Len() {
+		return "", fmt.Errorf("invalid string length %v", length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
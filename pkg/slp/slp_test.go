@@ -0,0 +1,233 @@
+package slp
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+// fakeResponder answers one server list ping, status is written as the status packet and pong turns the ping
+// payload into the pong payload
+type fakeResponder struct {
+	status func(w *bytes.Buffer)
+	pong   func(payload []byte) []byte
+}
+
+func statusJSON(payload string) func(w *bytes.Buffer) {
+	return func(w *bytes.Buffer) {
+		var body bytes.Buffer
+		writeString(&body, payload)
+		writePacket(w, packetIDStatus, body.Bytes())
+	}
+}
+
+func echoPong(payload []byte) []byte {
+	return payload
+}
+
+func startFakeResponder(t *testing.T, fr fakeResponder) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(testTimeout))
+		reader := bufio.NewReader(conn)
+
+		// handshake
+		id, data, err := readPacket(reader)
+		if err != nil || id != packetIDHandshake {
+			t.Errorf("expected a handshake, got packet %#x: %v", id, err)
+			return
+		}
+		body := bytes.NewReader(data)
+		if version, _ := readVarInt(body); version != protocolVersion {
+			t.Errorf("handshake protocol = %v, want %v", version, protocolVersion)
+		}
+		if host, _ := readString(body); host != "127.0.0.1" {
+			t.Errorf("handshake host = %q, want 127.0.0.1", host)
+		}
+
+		// status
+		if id, _, err := readPacket(reader); err != nil || id != packetIDStatus {
+			t.Errorf("expected a status request, got packet %#x: %v", id, err)
+			return
+		}
+		var status bytes.Buffer
+		fr.status(&status)
+		conn.Write(status.Bytes())
+
+		// ping
+		id, data, err = readPacket(reader)
+		if err != nil || id != packetIDPing {
+			return
+		}
+		var pong bytes.Buffer
+		writePacket(&pong, packetIDPing, fr.pong(data))
+		conn.Write(pong.Bytes())
+	}()
+
+	t.Cleanup(func() {
+		l.Close()
+		<-done
+	})
+
+	return l.Addr().String()
+}
+
+func TestPingPlainMOTD(t *testing.T) {
+	address := startFakeResponder(t, fakeResponder{
+		status: statusJSON(`{"version":{"name":"1.19.2","protocol":760},"players":{"max":20,"online":1,"sample":[{"name":"steve","id":"8667ba71-b85a-4004-af54-457a9734eed7"}]},"description":"A Minecraft Server"}`),
+		pong:   echoPong,
+	})
+
+	res, err := Ping(address, testTimeout)
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if res.MOTD != "A Minecraft Server" {
+		t.Errorf("MOTD = %q, want %q", res.MOTD, "A Minecraft Server")
+	}
+	if res.Version.Name != "1.19.2" || res.Version.Protocol != 760 {
+		t.Errorf("Version = %+v", res.Version)
+	}
+	if res.Players.Max != 20 || res.Players.Online != 1 || len(res.Players.Sample) != 1 || res.Players.Sample[0].Name != "steve" {
+		t.Errorf("Players = %+v", res.Players)
+	}
+}
+
+func TestPingChatComponentMOTD(t *testing.T) {
+	address := startFakeResponder(t, fakeResponder{
+		status: statusJSON(`{"version":{"name":"Paper 1.20.4","protocol":765},"players":{"max":50,"online":0},"description":{"text":"Hello ","extra":[{"text":"big","bold":true,"extra":[" world"]},{"text":"!","color":"gold"}]}}`),
+		pong:   echoPong,
+	})
+
+	res, err := Ping(address, testTimeout)
+	if err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if want := "Hello big world!"; res.MOTD != want {
+		t.Errorf("MOTD = %q, want %q", res.MOTD, want)
+	}
+}
+
+func TestPingMismatchedPong(t *testing.T) {
+	address := startFakeResponder(t, fakeResponder{
+		status: statusJSON(`{"version":{"name":"1.19.2","protocol":760},"players":{"max":20,"online":0},"description":""}`),
+		pong: func(payload []byte) []byte {
+			res := append([]byte{}, payload...)
+			res[len(res)-1]++
+			return res
+		},
+	})
+
+	if _, err := Ping(address, testTimeout); err == nil || !strings.Contains(err.Error(), "invalid pong") {
+		t.Fatalf("Ping() error = %v, want invalid pong", err)
+	}
+}
+
+func TestPingInvalidLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		status func(w *bytes.Buffer)
+	}{
+		{
+			name: "oversized packet length",
+			status: func(w *bytes.Buffer) {
+				writeVarInt(w, maxPacketSize+1)
+			},
+		},
+		{
+			name: "negative packet length",
+			status: func(w *bytes.Buffer) {
+				writeVarInt(w, -1)
+			},
+		},
+		{
+			name: "negative string length",
+			status: func(w *bytes.Buffer) {
+				var body bytes.Buffer
+				writeVarInt(&body, -5)
+				writePacket(w, packetIDStatus, body.Bytes())
+			},
+		},
+		{
+			name: "string longer than the packet",
+			status: func(w *bytes.Buffer) {
+				var body bytes.Buffer
+				writeVarInt(&body, 100)
+				body.WriteString("{}")
+				writePacket(w, packetIDStatus, body.Bytes())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := startFakeResponder(t, fakeResponder{status: tt.status, pong: echoPong})
+
+			if _, err := Ping(address, testTimeout); err == nil || !strings.Contains(err.Error(), "length") {
+				t.Fatalf("Ping() error = %v, want an invalid length error", err)
+			}
+		})
+	}
+}
+
+func TestVarIntRoundTrip(t *testing.T) {
+	tests := []struct {
+		value int
+		size  int
+	}{
+		{value: 0, size: 1},
+		{value: 1, size: 1},
+		{value: 127, size: 1},
+		{value: 128, size: 2},
+		{value: 255, size: 2},
+		{value: 25565, size: 3},
+		{value: 2097151, size: 3},
+		{value: 2147483647, size: 5},
+		{value: -1, size: 5},
+		{value: -2147483648, size: 5},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		writeVarInt(&buf, tt.value)
+		if buf.Len() != tt.size {
+			t.Errorf("writeVarInt(%v) wrote %v bytes, want %v", tt.value, buf.Len(), tt.size)
+		}
+
+		got, err := readVarInt(&buf)
+		if err != nil {
+			t.Errorf("readVarInt() of %v error = %v", tt.value, err)
+			continue
+		}
+		if got != tt.value {
+			t.Errorf("readVarInt() = %v, want %v", got, tt.value)
+		}
+	}
+}
+
+func TestReadVarIntTooBig(t *testing.T) {
+	if _, err := readVarInt(bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x01})); err == nil {
+		t.Fatal("readVarInt() error = nil, want varint is too big")
+	}
+}